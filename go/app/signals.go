@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// shutdownSignals are the signals that stop a run.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// withShutdownSignals handles SIGINT/SIGTERM in two stages. The first signal cancels
+// the returned stop context: no new URLs are started, in-flight requests finish and
+// partial results are saved. A second signal also cancels the returned run context,
+// aborting the requests still in flight.
+//
+// The release function restores the default signal behavior and must be called when
+// the application is done.
+func withShutdownSignals(parent context.Context) (ctx, stop context.Context, release func()) {
+	ctx, abort := context.WithCancel(parent)
+	stop, stopSignals := signal.NotifyContext(ctx, shutdownSignals...)
+
+	second := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-stop.Done():
+		case <-done:
+			return
+		}
+		if ctx.Err() != nil {
+			return // deadline or release, not a signal
+		}
+
+		signal.Notify(second, shutdownSignals...)
+		fmt.Fprintln(os.Stderr, "\n⏹️  Stopping: finishing requests in flight (press Ctrl+C again to abort)...")
+
+		select {
+		case <-second:
+			fmt.Fprintln(os.Stderr, "\n⏹️  Aborting requests in flight...")
+			abort()
+		case <-done:
+		}
+	}()
+
+	release = func() {
+		close(done)
+		signal.Stop(second)
+		stopSignals()
+		abort()
+	}
+	return ctx, stop, release
+}
@@ -0,0 +1,225 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-scraper/config"
+	"go-scraper/core"
+	"go-scraper/models"
+	"go-scraper/ui"
+	"go-scraper/util"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// runWatch re-scrapes the configured URL list on a schedule until it is stopped:
+//  1. Display header, load configuration and apply flag overrides once
+//  2. Use the latest results in the results directory as the baseline, if there are any
+//  3. On every run reload the URL list, scrape it without prompts and compare the
+//     pages with the previous run
+//  4. Save the results only when something changed (or there is no baseline yet)
+//  5. Record the run's summary in last-run.json in the results directory and wait
+//     for the next scheduled run
+//
+// The URL list is read again for every run, so edits take effect without a restart.
+// Once stop is done the current run finishes its requests in flight and watch mode
+// ends; its partial results are neither compared nor saved.
+func runWatch(ctx, stop context.Context, args []string) error {
+	opts, err := parseWatchFlags(args)
+	if err != nil {
+		return err
+	}
+
+	fs := util.OSFileSystem{}
+	tp := util.RealTimeProvider{}
+
+	cfg, err := loadConfig(opts.configFile, false)
+	if err != nil {
+		return err
+	}
+	if err := opts.applyTo(cfg); err != nil {
+		return err
+	}
+	lastRunPath := util.LastRunPath(cfg.ResultsDirectory)
+	if opts.status {
+		return printLastRun(fs, lastRunPath)
+	}
+	if cfg.UrlsFile == util.StdinPath {
+		return errors.New("watch mode cannot read URLs from stdin: configure a URL file")
+	}
+
+	// The schedule was checked when the configuration was validated
+	schedule, _ := cfg.Watch.Schedule()
+	mode := defaultNonInteractiveMode
+	if opts.isSet("mode") {
+		// Already validated while parsing flags
+		mode, _ = ui.ParseScrapeModeName(opts.mode)
+	}
+	if mode == ui.ModeLinkCheck {
+		return errors.New("link-check mode is not supported in watch mode")
+	}
+
+	ui.PrintHeader()
+	ui.PrintSeparator()
+	fmt.Printf("👀  Watching %s in %s mode, %s\n", cfg.UrlsFile, mode.String(), formatWatch(cfg.Watch))
+
+	previous := loadWatchBaseline(fs, cfg.ResultsDirectory)
+	transport := newTransport(cfg.Proxy)
+
+	for run := 1; ; run++ {
+		ui.PrintSeparator()
+		fmt.Printf("🔄  Run %d started at %s\n", run, time.Now().Format(time.DateTime))
+
+		var lastRun *util.LastRun
+		lastRun, previous = watchOnce(ctx, stop, cfg, transport, mode, fs, tp, previous, run == 1)
+		lastRun.Run = run
+
+		stopped := stop.Err() != nil || ctx.Err() != nil
+		if !stopped {
+			lastRun.NextRunAt = schedule.Next(time.Now())
+		}
+		if err := lastRun.Save(fs, lastRunPath); err != nil {
+			fmt.Println("⚠️  Last run not recorded:", err)
+		}
+		if stopped {
+			fmt.Println("⏹️  Watch mode stopped.")
+			return nil
+		}
+
+		fmt.Printf("⏰  Next run at %s\n", lastRun.NextRunAt.Format(time.DateTime))
+		timer := time.NewTimer(time.Until(lastRun.NextRunAt))
+		select {
+		case <-timer.C:
+		case <-stop.Done():
+			timer.Stop()
+			fmt.Println("⏹️  Watch mode stopped.")
+			return nil
+		}
+	}
+}
+
+// watchOnce performs a single run of watch mode and compares its pages with the
+// previous ones. The results are saved when they differ or there are no previous
+// pages. Returns the description of the run and the pages to compare the next run
+// with: the new pages if they were saved and the previous ones otherwise.
+func watchOnce(ctx, stop context.Context, cfg *config.ScrapeConfig, transport http.RoundTripper, mode ui.ScrapeMode,
+	fs util.FileSystem, tp util.TimeProvider, previous []*models.Page, showConfig bool) (*util.LastRun, []*models.Page) {
+	lastRun := &util.LastRun{StartedAt: time.Now()}
+	fail := func(err error) (*util.LastRun, []*models.Page) {
+		fmt.Println("🚫 ", err)
+		lastRun.Error = err.Error()
+		lastRun.FinishedAt = time.Now()
+		return lastRun, previous
+	}
+
+	// Reload the URL list so changes are picked up by the next run
+	urlList, err := newURLLoader(ctx, cfg, transport).Load(cfg.UrlsFile)
+	if err != nil {
+		return fail(fmt.Errorf("URLs could not be loaded from %s: %w", cfg.UrlsFile, err))
+	}
+	if showConfig {
+		printConfig(cfg, urlList)
+	} else {
+		fmt.Printf("📄  URLs File: %s (%d urls loaded)\n", cfg.UrlsFile, len(urlList.Targets))
+		printURLReport(urlList)
+	}
+	for _, target := range urlList.Targets {
+		if _, err := core.NewFieldExtractor(target.Extract); err != nil {
+			return fail(fmt.Errorf("invalid extract rules for %s: %w", target.URL, err))
+		}
+	}
+	if len(urlList.Targets) == 0 {
+		return fail(fmt.Errorf("no URLs configured in %s", cfg.UrlsFile))
+	}
+	ui.PrintSeparator()
+
+	// Bound the run; the pages are kept in memory to compare them with the next run
+	runCtx := ctx
+	if cfg.RunTimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, time.Duration(cfg.RunTimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+	summary := &models.Summary{}
+	pages := runScraper(runCtx, mode, urlList.Targets, cfg, newFetcher(cfg, transport),
+		core.WithSink(core.SinkFunc(func(page *models.Page) error {
+			summary.Add(page)
+			return nil
+		})),
+		core.WithGracefulStop(stop),
+		core.WithURLTimeout(time.Duration(cfg.PerURLTimeoutSeconds)*time.Second))
+
+	fmt.Println()
+	summary.Interrupted = stop.Err() != nil || runCtx.Err() != nil
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		fmt.Printf("⏱️  Run timeout of %ds reached\n", cfg.RunTimeoutSeconds)
+	}
+	lastRun.FinishedAt = time.Now()
+	lastRun.Summary = summary
+	printSummary(summary, lastRun.FinishedAt.Sub(lastRun.StartedAt))
+	ui.PrintSeparator()
+
+	// Partial results would show the missing pages as removed
+	if summary.Interrupted {
+		fmt.Println("👉  Run interrupted: results not compared or saved.")
+		return lastRun, previous
+	}
+
+	if previous != nil {
+		diff := models.DiffPages(previous, pages)
+		lastRun.Added, lastRun.Removed, lastRun.ChangedPages = len(diff.Added), len(diff.Removed), len(diff.Changed)
+		if !diff.HasChanges() {
+			fmt.Println("👉  No changes since the previous run: results not saved.")
+			return lastRun, previous
+		}
+		diff.Old, diff.New = "previous run", "this run"
+		ui.RenderDiff(os.Stdout, diff)
+	}
+
+	lastRun.Changed = true
+	lastRun.ResultFiles = saveResults(fs, tp, cfg, nil, pages)
+	return lastRun, pages
+}
+
+// loadWatchBaseline reads the results of the latest run in the results directory, so
+// a restarted watch mode only saves results that differ from the ones it saved before.
+// Returns nil if there are no (readable) results.
+func loadWatchBaseline(fs util.FileSystem, dir string) []*models.Page {
+	files, err := latestResultFiles(dir, 1)
+	if err != nil || len(files) == 0 {
+		return nil
+	}
+	pages, err := util.LoadResults(fs, files[0])
+	if err != nil {
+		fmt.Println("⚠️  Previous results ignored:", err)
+		return nil
+	}
+	fmt.Println("📂  Comparing with previous results:", files[0])
+	return pages
+}
+
+// printLastRun prints the description of the latest watch run as JSON.
+func printLastRun(fs util.FileSystem, path string) error {
+	lastRun, err := util.LoadLastRun(fs, path)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(lastRun, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize last run: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// formatWatch describes the schedule of watch mode (e.g. "every 1h0m0s" or "cron 0 * * * *").
+func formatWatch(watch config.WatchConfig) string {
+	if cron := strings.TrimSpace(watch.Cron); cron != "" {
+		return "cron " + cron
+	}
+	return "every " + (time.Duration(watch.IntervalSeconds) * time.Second).String()
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"go-scraper/config"
+	"go-scraper/models"
+	"go-scraper/ui"
+	"go-scraper/util"
+)
+
+// stepTimeProvider advances by one second on every call, so saved result files
+// get distinct names.
+type stepTimeProvider struct{ now atomic.Int64 }
+
+func (tp *stepTimeProvider) NowUnixMilli() int64 { return tp.now.Add(1000) }
+
+func TestWatchOnce_SavesWhenOnlyAFieldChanges(t *testing.T) {
+	var price atomic.Value
+	price.Store("10")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><head><title>Item</title></head><body><span class="price">` +
+			price.Load().(string) + `</span></body></html>`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	urlsFile := filepath.Join(dir, "urls.txt")
+	if err := os.WriteFile(urlsFile, []byte(server.URL+"/item\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.NewDefaultConfig()
+	cfg.UrlsFile = urlsFile
+	cfg.ResultsDirectory = filepath.Join(dir, "results")
+	cfg.RespectRobotsTxt = false
+	cfg.Extract = config.ExtractConfig{"price": models.ExtractRule{Selector: ".price"}}
+
+	fs, tp := util.OSFileSystem{}, &stepTimeProvider{}
+	run := func(previous []*models.Page) (*util.LastRun, []*models.Page) {
+		return watchOnce(context.Background(), context.Background(), cfg, nil, ui.ModeSequential, fs, tp, previous, false)
+	}
+
+	lastRun, pages := run(nil)
+	if !lastRun.Changed || len(pages) != 1 || pages[0].Fields["price"] != "10" {
+		t.Fatalf("expected the first run to be saved with the price, got %+v and %+v", lastRun, pages)
+	}
+
+	lastRun, pages = run(pages)
+	if lastRun.Changed {
+		t.Fatalf("expected an unchanged run not to be saved, got %+v", lastRun)
+	}
+
+	price.Store("12")
+	lastRun, pages = run(pages)
+	if !lastRun.Changed || lastRun.ChangedPages != 1 || len(lastRun.ResultFiles) == 0 {
+		t.Fatalf("expected a changed price to save the results, got %+v", lastRun)
+	}
+	if pages[0].Fields["price"] != "12" {
+		t.Errorf("expected the new pages as the next baseline, got %+v", pages[0].Fields)
+	}
+}
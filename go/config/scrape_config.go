@@ -4,8 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"go-scraper/core"
+	"go-scraper/models"
+	"go-scraper/util"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 )
 
 const (
@@ -17,6 +24,26 @@ const (
 	DefaultConcurrency = 5
 	// DefaultHTTPTimeoutSeconds is the default HTTP request timeout in seconds
 	DefaultHTTPTimeoutSeconds = 30
+	// DefaultRunTimeoutSeconds is the default maximum duration of a whole run in seconds
+	DefaultRunTimeoutSeconds = 600
+	// DefaultRetryMaxAttempts is the default number of attempts per URL including retries
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryInitialBackoffMs is the default delay before the first retry in milliseconds
+	DefaultRetryInitialBackoffMs = 500
+	// DefaultRetryMaxBackoffMs is the default maximum retry delay in milliseconds
+	DefaultRetryMaxBackoffMs = 10000
+	// DefaultCrawlMaxDepth is the default maximum link depth followed in crawl mode
+	DefaultCrawlMaxDepth = 2
+	// DefaultCrawlMaxPages is the default maximum number of pages scraped in crawl mode
+	DefaultCrawlMaxPages = 100
+	// DefaultCacheDirectory is the default directory of the HTTP response cache
+	DefaultCacheDirectory = ".cache"
+	// DefaultCacheMaxSizeMB is the default size limit of the HTTP response cache in megabytes
+	DefaultCacheMaxSizeMB = 500
+	// DefaultWatchIntervalSeconds is the default delay between two runs in watch mode in seconds
+	DefaultWatchIntervalSeconds = 3600
+	// DefaultProxyBenchSeconds is the default time a failed proxy is taken out of rotation in seconds
+	DefaultProxyBenchSeconds = 60
 	// DefaultUserAgent is the default User-Agent header for HTTP requests
 	DefaultUserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.0 Mobile/15E148 Safari/604.1"
 )
@@ -25,13 +52,105 @@ const (
 // It defines how the scraper should behave including concurrency limits, timeouts,
 // and file locations for input/output operations.
 type ScrapeConfig struct {
-	UrlsFile           string `json:"urlsFile"`           // Path to JSON file containing URLs to scrape
-	ResultsDirectory   string `json:"resultsDirectory"`   // Directory where scrape results will be saved
-	Concurrency        int    `json:"concurrency"`        // Number of concurrent workers for parallel scraping
-	HttpTimeoutSeconds int    `json:"httpTimeoutSeconds"` // HTTP request timeout in seconds
-	UserAgent          string `json:"userAgent"`          // User-Agent header for HTTP requests
+	UrlsFile             string                   `json:"urlsFile"`             // Path to JSON file containing URLs to scrape
+	ResultsDirectory     string                   `json:"resultsDirectory"`     // Directory where scrape results will be saved
+	Concurrency          int                      `json:"concurrency"`          // Number of concurrent workers for parallel scraping
+	HttpTimeoutSeconds   int                      `json:"httpTimeoutSeconds"`   // HTTP request timeout in seconds
+	RunTimeoutSeconds    int                      `json:"runTimeoutSeconds"`    // Maximum duration of a whole run in seconds (0 = unlimited)
+	PerURLTimeoutSeconds int                      `json:"perUrlTimeoutSeconds"` // Maximum time per URL including retries and parsing in seconds (0 = unlimited)
+	UserAgent            string                   `json:"userAgent"`            // User-Agent header for HTTP requests
+	RespectRobotsTxt     bool                     `json:"respectRobotsTxt"`     // Whether robots.txt rules and crawl delays are honored
+	RateLimit            RateLimitConfig          `json:"rateLimit"`            // Per-host politeness limits
+	Retry                RetryConfig              `json:"retry"`                // Retry behavior for transient fetch failures
+	Crawl                CrawlConfig              `json:"crawl"`                // Settings for crawl mode
+	Cache                CacheConfig              `json:"cache"`                // On-disk HTTP response cache
+	Watch                WatchConfig              `json:"watch"`                // Schedule of watch mode
+	Proxy                ProxyConfig              `json:"proxy"`                // Proxies requests are sent through
+	CaptureHeaders       []string                 `json:"captureHeaders"`       // Response headers recorded on each page
+	ExportFormats        []string                 `json:"exportFormats"`        // Formats written when results are saved (json, csv, ndjson, xml, markdown, html)
+	Extract              ExtractConfig            `json:"extract"`              // Custom fields extracted from every page
+	ExtractByHost        map[string]ExtractConfig `json:"extractByHost"`        // Additional custom fields per host (including subdomains)
+	StoreContentText     bool                     `json:"storeContentText"`     // Store the main-content text of each page (greatly increases result size)
 }
 
+// RateLimitConfig defines per-host request limits applied to every fetch,
+// in both sequential and parallel mode. Zero values disable the respective limit.
+type RateLimitConfig struct {
+	RequestsPerSecond  float64 `json:"requestsPerSecond"`  // Sustained requests per second per host (0 = unlimited)
+	Burst              int     `json:"burst"`              // Requests allowed in a burst before limiting applies
+	MaxInFlightPerHost int     `json:"maxInFlightPerHost"` // Maximum concurrent requests per host (0 = unlimited)
+}
+
+// RetryConfig defines how transient fetch failures (timeouts, connection resets and
+// retryable HTTP status codes) are retried with exponential backoff and jitter.
+type RetryConfig struct {
+	MaxAttempts       int   `json:"maxAttempts"`       // Total attempts per URL including the first (1 = no retries)
+	InitialBackoffMs  int   `json:"initialBackoffMs"`  // Delay before the first retry in milliseconds
+	MaxBackoffMs      int   `json:"maxBackoffMs"`      // Upper bound for the backoff delay in milliseconds
+	RetryableStatuses []int `json:"retryableStatuses"` // HTTP status codes that trigger a retry
+}
+
+// CrawlConfig defines how far crawl mode follows links discovered on the seed pages.
+type CrawlConfig struct {
+	MaxDepth       int      `json:"maxDepth"`       // Maximum link depth from a seed URL (0 = seeds only)
+	MaxPages       int      `json:"maxPages"`       // Maximum number of pages scraped in total
+	SameHost       bool     `json:"sameHost"`       // Only follow links to the hosts of the seed URLs
+	AllowedDomains []string `json:"allowedDomains"` // Additional domains (including subdomains) that may be followed
+}
+
+// CacheConfig defines the on-disk HTTP response cache. Cached pages are revalidated
+// with ETag / Last-Modified, so unchanged pages are not downloaded again.
+type CacheConfig struct {
+	Enabled       bool   `json:"enabled"`       // Whether responses are cached
+	Directory     string `json:"directory"`     // Directory holding the cache entries
+	MaxAgeSeconds int    `json:"maxAgeSeconds"` // Serve entries younger than this without revalidation (0 = always revalidate)
+	MaxSizeMB     int    `json:"maxSizeMb"`     // Size limit; least recently used entries are evicted (0 = unlimited)
+}
+
+// WatchConfig defines when watch mode re-runs the URL list. A cron expression takes
+// precedence over the interval.
+type WatchConfig struct {
+	IntervalSeconds int    `json:"intervalSeconds"` // Delay between the end of a run and the start of the next in seconds
+	Cron            string `json:"cron"`            // Cron expression (minute hour day month weekday), e.g. "0 */6 * * *"
+}
+
+// Schedule returns the schedule of watch mode described by the configuration.
+func (w WatchConfig) Schedule() (core.Schedule, error) {
+	schedule, err := core.NewSchedule(time.Duration(w.IntervalSeconds)*time.Second, w.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("watch: %w", err)
+	}
+	return schedule, nil
+}
+
+// ProxyConfig defines the proxies requests are sent through. Without proxy URLs the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are honored, unless
+// useEnvironment is disabled.
+type ProxyConfig struct {
+	URLs           []string `json:"urls"`           // Proxy URLs (http, https, socks5 or socks5h), credentials as user:password@host
+	Rotation       string   `json:"rotation"`       // How requests are spread over several proxies: round-robin or per-host
+	NoProxy        string   `json:"noProxy"`        // Comma-separated hosts, domains and CIDR ranges requested directly, like NO_PROXY
+	BenchSeconds   int      `json:"benchSeconds"`   // Time a failing proxy is skipped in seconds (0 = never skip)
+	UseEnvironment bool     `json:"useEnvironment"` // Use the proxy environment variables when no URLs are configured
+}
+
+// Pool returns the pool of the configured proxies, or nil if there are none.
+func (p ProxyConfig) Pool() (*core.ProxyPool, error) {
+	if len(p.URLs) == 0 {
+		return nil, nil
+	}
+	pool, err := core.NewProxyPool(p.URLs, core.ProxyRotation(p.Rotation), time.Duration(p.BenchSeconds)*time.Second, p.NoProxy)
+	if err != nil {
+		return nil, fmt.Errorf("proxy: %w", err)
+	}
+	return pool, nil
+}
+
+// ExtractConfig maps custom field names to the rules that extract them from a page.
+// A rule is a CSS selector or XPath string (text of the first match) or an object
+// with "selector" or "xpath", "attr" and "multiple" (see models.ExtractRule).
+type ExtractConfig map[string]models.ExtractRule
+
 // NewDefaultConfig creates a ScrapeConfig with sensible default values.
 // Use this when no configuration file exists or when you need a baseline configuration.
 func NewDefaultConfig() *ScrapeConfig {
@@ -40,11 +159,53 @@ func NewDefaultConfig() *ScrapeConfig {
 		ResultsDirectory:   DefaultResultsDirectory,
 		Concurrency:        DefaultConcurrency,
 		HttpTimeoutSeconds: DefaultHTTPTimeoutSeconds,
+		RunTimeoutSeconds:  DefaultRunTimeoutSeconds,
 		UserAgent:          DefaultUserAgent,
+		RespectRobotsTxt:   true,
+		RateLimit: RateLimitConfig{
+			RequestsPerSecond:  0,
+			Burst:              1,
+			MaxInFlightPerHost: 0,
+		},
+		Retry: RetryConfig{
+			MaxAttempts:       DefaultRetryMaxAttempts,
+			InitialBackoffMs:  DefaultRetryInitialBackoffMs,
+			MaxBackoffMs:      DefaultRetryMaxBackoffMs,
+			RetryableStatuses: slices.Clone(core.DefaultRetryableStatuses), // A copy, since decoding the config file reuses the slice
+		},
+		Crawl: CrawlConfig{
+			MaxDepth:       DefaultCrawlMaxDepth,
+			MaxPages:       DefaultCrawlMaxPages,
+			SameHost:       true,
+			AllowedDomains: []string{},
+		},
+		Cache: CacheConfig{
+			Enabled:       false,
+			Directory:     DefaultCacheDirectory,
+			MaxAgeSeconds: 0,
+			MaxSizeMB:     DefaultCacheMaxSizeMB,
+		},
+		Watch: WatchConfig{
+			IntervalSeconds: DefaultWatchIntervalSeconds,
+			Cron:            "",
+		},
+		Proxy: ProxyConfig{
+			URLs:           []string{},
+			Rotation:       string(core.ProxyRoundRobin),
+			NoProxy:        "",
+			BenchSeconds:   DefaultProxyBenchSeconds,
+			UseEnvironment: true,
+		},
+		CaptureHeaders:   slices.Clone(core.DefaultCaptureHeaders), // A copy, since decoding the config file reuses the slice
+		ExportFormats:    []string{util.DefaultExportFormat},
+		Extract:          ExtractConfig{},
+		ExtractByHost:    map[string]ExtractConfig{},
+		StoreContentText: false,
 	}
 }
 
 // LoadConfig reads and parses a configuration file from the specified path.
+// Settings missing from the file keep their default values from NewDefaultConfig().
 // It validates the configuration after loading and returns an error if validation fails.
 // If the file doesn't exist or is invalid, consider using NewDefaultConfig() as a fallback.
 func LoadConfig(path string) (*ScrapeConfig, error) {
@@ -53,8 +214,8 @@ func LoadConfig(path string) (*ScrapeConfig, error) {
 		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
 
-	var cfg ScrapeConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	cfg := NewDefaultConfig()
+	if err := json.Unmarshal(data, cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config JSON from %s: %w", path, err)
 	}
 
@@ -62,7 +223,7 @@ func LoadConfig(path string) (*ScrapeConfig, error) {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
-	return &cfg, nil
+	return cfg, nil
 }
 
 // SaveConfig writes a configuration object to a JSON file at the specified path.
@@ -92,12 +253,95 @@ func (c *ScrapeConfig) Validate() error {
 	if c.HttpTimeoutSeconds <= 0 {
 		return errors.New("httpTimeoutSeconds must be greater than zero")
 	}
+	if c.RunTimeoutSeconds < 0 {
+		return errors.New("runTimeoutSeconds must not be negative")
+	}
+	if c.PerURLTimeoutSeconds < 0 {
+		return errors.New("perUrlTimeoutSeconds must not be negative")
+	}
 	if c.UserAgent == "" {
 		return errors.New("userAgent is required")
 	}
+	if c.RateLimit.RequestsPerSecond < 0 {
+		return errors.New("rateLimit.requestsPerSecond must not be negative")
+	}
+	if c.RateLimit.Burst < 0 {
+		return errors.New("rateLimit.burst must not be negative")
+	}
+	if c.RateLimit.MaxInFlightPerHost < 0 {
+		return errors.New("rateLimit.maxInFlightPerHost must not be negative")
+	}
+	if c.Retry.MaxAttempts <= 0 {
+		return errors.New("retry.maxAttempts must be greater than zero")
+	}
+	if c.Retry.InitialBackoffMs < 0 || c.Retry.MaxBackoffMs < 0 {
+		return errors.New("retry backoff values must not be negative")
+	}
+	if c.Crawl.MaxDepth < 0 {
+		return errors.New("crawl.maxDepth must not be negative")
+	}
+	if c.Crawl.MaxPages <= 0 {
+		return errors.New("crawl.maxPages must be greater than zero")
+	}
+	if c.Cache.Enabled && c.Cache.Directory == "" {
+		return errors.New("cache.directory is required when the cache is enabled")
+	}
+	if c.Cache.MaxAgeSeconds < 0 || c.Cache.MaxSizeMB < 0 {
+		return errors.New("cache.maxAgeSeconds and cache.maxSizeMb must not be negative")
+	}
+	if c.Watch.IntervalSeconds < 0 {
+		return errors.New("watch.intervalSeconds must not be negative")
+	}
+	if _, err := c.Watch.Schedule(); err != nil {
+		return err
+	}
+	if c.Proxy.BenchSeconds < 0 {
+		return errors.New("proxy.benchSeconds must not be negative")
+	}
+	if _, err := c.Proxy.Pool(); err != nil {
+		return err
+	}
+	if len(c.ExportFormats) == 0 {
+		return errors.New("exportFormats must contain at least one format")
+	}
+	for _, format := range c.ExportFormats {
+		if _, ok := util.ExporterFor(format); !ok {
+			return fmt.Errorf("exportFormats: unknown format %q (supported: %s)", format, strings.Join(util.ExportFormats(), ", "))
+		}
+	}
+	if _, _, err := c.FieldExtractors(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// FieldExtractors compiles the configured extraction rules: the global rules and,
+// keyed by lowercase host name, the global rules plus the rules of each host.
+func (c *ScrapeConfig) FieldExtractors() (*core.FieldExtractor, map[string]*core.FieldExtractor, error) {
+	global, err := core.NewFieldExtractor(c.Extract)
+	if err != nil {
+		return nil, nil, fmt.Errorf("extract: %w", err)
+	}
+
+	var byHost map[string]*core.FieldExtractor
+	for _, host := range slices.Sorted(maps.Keys(c.ExtractByHost)) {
+		rules := c.ExtractByHost[host]
+		key := strings.Trim(strings.ToLower(strings.TrimSpace(host)), ".")
+		if key == "" {
+			return nil, nil, errors.New("extractByHost: host name must not be empty")
+		}
+		fields, err := global.With(rules)
+		if err != nil {
+			return nil, nil, fmt.Errorf("extractByHost[%q]: %w", host, err)
+		}
+		if byHost == nil {
+			byHost = make(map[string]*core.FieldExtractor)
+		}
+		byHost[key] = fields
+	}
+	return global, byHost, nil
+}
+
 // writeConfigFile writes a config struct to disk as JSON.
 func writeConfigFile(path string, cfg *ScrapeConfig) error {
 	if cfg == nil {
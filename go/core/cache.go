@@ -0,0 +1,251 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheFileSuffix is the file name extension of cache entries.
+const cacheFileSuffix = ".json"
+
+// CachingFetcher is an HTTPFetcher decorator that keeps successful GET responses on
+// disk, keyed by URL, so repeated runs do not download unchanged pages again.
+//
+// A cached page is revalidated with If-None-Match / If-Modified-Since built from its
+// ETag and Last-Modified headers; a 304 Not Modified answer is served from the cache.
+// Entries younger than MaxAge are served without any request. When the cache grows
+// beyond MaxBytes, the least recently used entries are evicted. Results served from
+// the cache have FromCache set.
+type CachingFetcher struct {
+	Fetcher  HTTPFetcher   // Underlying fetcher
+	Dir      string        // Directory holding one file per cached URL
+	MaxAge   time.Duration // Serve entries younger than this without revalidation (0 = always revalidate)
+	MaxBytes int64         // Size limit of the cache directory (0 = unlimited)
+
+	mu      sync.Mutex
+	entries map[string]cacheFile // by file name
+	size    int64
+}
+
+// cacheFile is the bookkeeping of a cache file for LRU eviction.
+type cacheFile struct {
+	size     int64
+	lastUsed time.Time
+}
+
+// cacheEntry is the on-disk representation of a cached response.
+type cacheEntry struct {
+	URL           string      `json:"url"`
+	FinalURL      string      `json:"finalUrl"`
+	StatusCode    int         `json:"statusCode"`
+	Header        http.Header `json:"header"`
+	RedirectChain []string    `json:"redirectChain,omitempty"`
+	StoredAt      time.Time   `json:"storedAt"` // Time of the last download or revalidation
+	Body          []byte      `json:"body"`
+}
+
+// NewCachingFetcher wraps fetcher with a disk cache in dir, creating the directory
+// if needed. Existing entries are picked up, with their modification times as the
+// last use for LRU eviction.
+func NewCachingFetcher(fetcher HTTPFetcher, dir string, maxAge time.Duration, maxBytes int64) (*CachingFetcher, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory %s: %w", dir, err)
+	}
+
+	c := &CachingFetcher{
+		Fetcher:  fetcher,
+		Dir:      dir,
+		MaxAge:   maxAge,
+		MaxBytes: maxBytes,
+		entries:  make(map[string]cacheFile),
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), cacheFileSuffix) {
+			continue
+		}
+		if info, err := file.Info(); err == nil {
+			c.entries[file.Name()] = cacheFile{size: info.Size(), lastUsed: info.ModTime()}
+			c.size += info.Size()
+		}
+	}
+	return c, nil
+}
+
+// Fetch serves rawURL from the cache when possible and otherwise delegates to the
+// wrapped fetcher, storing successful responses. Requests other than GET bypass the
+// cache. Cache read and write failures never fail the fetch; the page is simply
+// downloaded (and not cached).
+func (c *CachingFetcher) Fetch(ctx context.Context, rawURL string) (*FetchResult, error) {
+	opts := requestOptionsFrom(ctx)
+	if opts.Method != "" && opts.Method != http.MethodGet {
+		return c.Fetcher.Fetch(ctx, rawURL)
+	}
+
+	name := cacheFileName(rawURL)
+	entry := c.load(name)
+	if entry != nil && c.MaxAge > 0 && time.Since(entry.StoredAt) < c.MaxAge {
+		c.touch(name)
+		return entry.result(), nil
+	}
+
+	if entry != nil {
+		// Ask the server to confirm that the cached copy is still current
+		headers := make(map[string]string, len(opts.Headers)+2)
+		maps.Copy(headers, opts.Headers)
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			headers["If-None-Match"] = etag
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			headers["If-Modified-Since"] = lastModified
+		}
+		opts.Headers = headers
+		ctx = WithRequestOptions(ctx, opts)
+	}
+
+	result, err := c.Fetcher.Fetch(ctx, rawURL)
+
+	var statusErr *HTTPStatusError
+	if entry != nil && errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotModified {
+		entry.StoredAt = time.Now()
+		c.store(name, entry)
+		cached := entry.result()
+		if result != nil {
+			cached.Attempts = result.Attempts
+			cached.Timing = result.Timing
+		}
+		return cached, nil
+	}
+
+	if err == nil && result.StatusCode == http.StatusOK {
+		c.store(name, &cacheEntry{
+			URL:           rawURL,
+			FinalURL:      result.FinalURL,
+			StatusCode:    result.StatusCode,
+			Header:        result.Header,
+			RedirectChain: result.RedirectChain,
+			StoredAt:      time.Now(),
+			Body:          result.Body,
+		})
+	}
+	return result, err
+}
+
+// result converts a cache entry into a fetch result served from the cache.
+func (e *cacheEntry) result() *FetchResult {
+	return &FetchResult{
+		Body:          e.Body,
+		FinalURL:      e.FinalURL,
+		StatusCode:    e.StatusCode,
+		Header:        e.Header,
+		RedirectChain: e.RedirectChain,
+		FromCache:     true,
+	}
+}
+
+// cacheFileName derives the file name of a URL's cache entry.
+func cacheFileName(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:]) + cacheFileSuffix
+}
+
+// load reads a cache entry. Missing or unreadable entries yield nil.
+func (c *CachingFetcher) load(name string) *cacheEntry {
+	data, err := os.ReadFile(filepath.Join(c.Dir, name))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// store writes a cache entry atomically and evicts least recently used entries
+// while the cache exceeds MaxBytes.
+func (c *CachingFetcher) store(name string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if c.MaxBytes > 0 && int64(len(data)) > c.MaxBytes {
+		return // would evict everything else and still not fit
+	}
+
+	path := filepath.Join(c.Dir, name)
+	tmp, err := os.CreateTemp(c.Dir, name+".tmp*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), path) != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size += int64(len(data)) - c.entries[name].size
+	c.entries[name] = cacheFile{size: int64(len(data)), lastUsed: time.Now()}
+	c.evict(name)
+}
+
+// touch marks an entry as used, both in memory and via its modification time,
+// so the LRU order survives restarts.
+func (c *CachingFetcher) touch(name string) {
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(c.Dir, name), now, now)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if file, ok := c.entries[name]; ok {
+		file.lastUsed = now
+		c.entries[name] = file
+	}
+}
+
+// evict removes the least recently used entries, except keep, until the cache
+// fits into MaxBytes. The caller must hold c.mu.
+func (c *CachingFetcher) evict(keep string) {
+	if c.MaxBytes <= 0 || c.size <= c.MaxBytes {
+		return
+	}
+
+	names := make([]string, 0, len(c.entries))
+	for name := range c.entries {
+		if name != keep {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return c.entries[names[i]].lastUsed.Before(c.entries[names[j]].lastUsed)
+	})
+
+	for _, name := range names {
+		if c.size <= c.MaxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.Dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		c.size -= c.entries[name].size
+		delete(c.entries, name)
+	}
+}
@@ -0,0 +1,134 @@
+package core_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-scraper/core"
+)
+
+// etagServer serves a page with an ETag and answers matching conditional requests
+// with 304 Not Modified. It counts full responses and 304s.
+type etagServer struct {
+	*httptest.Server
+	full, notModified atomic.Int32
+}
+
+func newETagServer(t *testing.T) *etagServer {
+	s := &etagServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"v1` + r.URL.Path + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			s.notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		s.full.Add(1)
+		_, _ = w.Write([]byte("<html><title>" + r.URL.Path + "</title>" + strings.Repeat(" ", 100) + "</html>"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newCache(t *testing.T, dir string, maxAge time.Duration, maxBytes int64) *core.CachingFetcher {
+	t.Helper()
+	cache, err := core.NewCachingFetcher(core.NewFetcher(2*time.Second, "UserAgent"), dir, maxAge, maxBytes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return cache
+}
+
+func TestCachingFetcher_Revalidates(t *testing.T) {
+	server := newETagServer(t)
+	dir := t.TempDir()
+
+	first, err := newCache(t, dir, 0, 0).Fetch(context.Background(), server.URL+"/a")
+	if err != nil || first.FromCache {
+		t.Fatalf("expected a downloaded page, got %+v (err %v)", first, err)
+	}
+
+	// A new instance picks up the entries of the previous run
+	second, err := newCache(t, dir, 0, 0).Fetch(context.Background(), server.URL+"/a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !second.FromCache || second.StatusCode != http.StatusOK || string(second.Body) != string(first.Body) || second.Attempts != 1 {
+		t.Errorf("expected the cached page after a 304, got %+v", second)
+	}
+	if server.full.Load() != 1 || server.notModified.Load() != 1 {
+		t.Errorf("expected 1 full response and 1 revalidation, got %d and %d", server.full.Load(), server.notModified.Load())
+	}
+}
+
+func TestCachingFetcher_MaxAge(t *testing.T) {
+	server := newETagServer(t)
+	cache := newCache(t, t.TempDir(), time.Hour, 0)
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.Fetch(context.Background(), server.URL+"/a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if server.full.Load() != 1 || server.notModified.Load() != 0 {
+		t.Errorf("expected fresh entries to be served without requests, got %d full and %d revalidations",
+			server.full.Load(), server.notModified.Load())
+	}
+}
+
+func TestCachingFetcher_BypassesOtherMethods(t *testing.T) {
+	server := newETagServer(t)
+	cache := newCache(t, t.TempDir(), time.Hour, 0)
+	ctx := core.WithRequestOptions(context.Background(), core.RequestOptions{Method: http.MethodPost})
+
+	for i := 0; i < 2; i++ {
+		if result, err := cache.Fetch(ctx, server.URL+"/a"); err != nil || result.FromCache {
+			t.Fatalf("expected POST to bypass the cache, got %+v (err %v)", result, err)
+		}
+	}
+	if server.full.Load() != 2 {
+		t.Errorf("expected 2 downloads, got %d", server.full.Load())
+	}
+}
+
+func TestCachingFetcher_EvictsLeastRecentlyUsed(t *testing.T) {
+	server := newETagServer(t)
+	dir := t.TempDir()
+
+	// Measure the size of one entry to size the cache for two of them
+	probe := newCache(t, t.TempDir(), time.Hour, 0)
+	if _, err := probe.Fetch(context.Background(), server.URL+"/a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, _ := os.ReadDir(probe.Dir)
+	info, _ := entries[0].Info()
+
+	cache := newCache(t, dir, time.Hour, 2*info.Size()+info.Size()/2)
+	for _, path := range []string{"/a", "/b", "/a", "/c"} {
+		if _, err := cache.Fetch(context.Background(), server.URL+path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond) // distinct access times
+	}
+	server.full.Store(0)
+
+	// /b was used least recently and must have been evicted, /a and /c are cached
+	for _, path := range []string{"/a", "/c", "/b"} {
+		if _, err := cache.Fetch(context.Background(), server.URL+path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if server.full.Load() != 1 {
+		t.Errorf("expected only the evicted page to be downloaded again, got %d downloads", server.full.Load())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("expected 2 cache entries, got %d", len(entries))
+	}
+}
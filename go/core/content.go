@@ -0,0 +1,214 @@
+package core
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+
+	"go-scraper/models"
+)
+
+// WordsPerMinute is the reading speed used to estimate the reading time of a page.
+const WordsPerMinute = 200
+
+// contentSkipElements never contribute readable text or headings.
+var contentSkipElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "svg": true, "canvas": true,
+	"iframe": true, "object": true, "embed": true, "nav": true, "aside": true, "form": true,
+	"button": true, "select": true, "textarea": true, "dialog": true,
+}
+
+// boilerplateRoles are ARIA landmark roles of page chrome rather than content.
+var boilerplateRoles = map[string]bool{
+	"navigation": true, "banner": true, "contentinfo": true, "complementary": true, "search": true,
+}
+
+// boilerplateMarkers identify page chrome by a fragment of an element's class or id.
+var boilerplateMarkers = []string{
+	"cookie", "consent", "sidebar", "breadcrumb", "share", "social", "advert", "newsletter", "popup", "modal",
+}
+
+// headingLevels maps the heading elements to their outline level.
+var headingLevels = map[string]int{"h1": 1, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6}
+
+// contentRoots records the candidate containers of the main content, found while
+// the document is traversed.
+type contentRoots struct {
+	body     *html.Node
+	main     *html.Node // first <main> or role="main" element
+	article  *html.Node // first <article> element
+	articles int
+}
+
+// visit records n if it is a content container.
+func (r *contentRoots) visit(n *html.Node) {
+	switch {
+	case n.Data == "body":
+		if r.body == nil {
+			r.body = n
+		}
+	case n.Data == "main" || strings.EqualFold(attrValue(n, "role"), "main"):
+		if r.main == nil {
+			r.main = n
+		}
+	case n.Data == "article":
+		if r.article == nil {
+			r.article = n
+		}
+		r.articles++
+	}
+}
+
+// root returns the element holding the main content: the <main> element, the only
+// <article>, or else the whole <body>.
+func (r *contentRoots) root() *html.Node {
+	switch {
+	case r.main != nil:
+		return r.main
+	case r.articles == 1:
+		return r.article
+	default:
+		return r.body
+	}
+}
+
+// analyzeContent extracts the readable text of the main content together with its
+// statistics and the heading outline of the page. Boilerplate (scripts, navigation,
+// sidebars, page header and footer, hidden elements, ...) is skipped. The text is
+// only kept in the result if storeText is set. Returns nil for documents without a body.
+func analyzeContent(roots contentRoots, storeText bool) *models.Content {
+	if roots.body == nil {
+		return nil
+	}
+	root := roots.root()
+	// Containers of the main content are never skipped, whatever their class names say
+	keep := make(map[*html.Node]bool)
+	for n := root; n != nil; n = n.Parent {
+		keep[n] = true
+	}
+
+	var text blockText
+	var headings []models.Heading
+	var walk func(n *html.Node, inRoot, inArticle bool)
+	walk = func(n *html.Node, inRoot, inArticle bool) {
+		if n.Type == html.TextNode {
+			if inRoot {
+				text.write(n.Data)
+			}
+			return
+		}
+		if n.Type != html.ElementNode {
+			return
+		}
+		if !keep[n] && isBoilerplate(n, inArticle) {
+			return
+		}
+
+		inRoot = inRoot || n == root
+		inArticle = inArticle || n.Data == "article"
+		if level, ok := headingLevels[n.Data]; ok {
+			if heading := nodeText(n); heading != "" {
+				headings = append(headings, models.Heading{Level: level, Text: heading})
+			}
+		}
+
+		block := blockElements[n.Data]
+		if block && inRoot {
+			text.flush()
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inRoot, inArticle)
+		}
+		if block && inRoot {
+			text.flush()
+		}
+	}
+	walk(roots.body, false, false)
+	text.flush()
+
+	words := countWords(text.blocks)
+	content := &models.Content{
+		WordCount:          words,
+		ReadingTimeSeconds: int(math.Ceil(float64(words) * 60 / WordsPerMinute)),
+		Headings:           headings,
+		Language:           detectLanguage(text.blocks),
+	}
+	if storeText {
+		content.Text = strings.Join(text.blocks, "\n")
+	}
+	return content
+}
+
+// isBoilerplate reports whether the element is page chrome rather than content.
+// <header> and <footer> only count as boilerplate outside of an <article>.
+func isBoilerplate(n *html.Node, inArticle bool) bool {
+	if contentSkipElements[n.Data] {
+		return true
+	}
+	if (n.Data == "header" || n.Data == "footer") && !inArticle {
+		return true
+	}
+
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "hidden":
+			return true
+		case "aria-hidden":
+			if strings.EqualFold(attr.Val, "true") {
+				return true
+			}
+		case "role":
+			if boilerplateRoles[strings.ToLower(attr.Val)] {
+				return true
+			}
+		case "class", "id":
+			value := strings.ToLower(attr.Val)
+			for _, marker := range boilerplateMarkers {
+				if strings.Contains(value, marker) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// blockText assembles text into blocks (paragraphs, list items, headings, ...)
+// with whitespace collapsed.
+type blockText struct {
+	blocks []string
+	line   strings.Builder
+}
+
+// write appends text to the current block.
+func (t *blockText) write(s string) {
+	t.line.WriteString(s)
+}
+
+// flush ends the current block, dropping it if it holds no text.
+func (t *blockText) flush() {
+	if block := strings.Join(strings.Fields(t.line.String()), " "); block != "" {
+		t.blocks = append(t.blocks, block)
+	}
+	t.line.Reset()
+}
+
+// countWords counts the whitespace-separated tokens that contain a letter or digit.
+func countWords(blocks []string) int {
+	count := 0
+	for _, block := range blocks {
+		for _, token := range strings.Fields(block) {
+			if strings.IndexFunc(token, isWordRune) >= 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// isWordRune reports whether r is a letter or digit.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,99 @@
+package core_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-scraper/core"
+	"go-scraper/models"
+)
+
+const articleHTML = `
+<html>
+  <head><title>Post</title><style>p { color: red }</style></head>
+  <body>
+    <header><a href="/">Site logo</a></header>
+    <nav><ul><li>Home</li><li>Blog</li></ul></nav>
+    <div class="layout has-sidebar">
+      <main>
+        <article>
+          <header><h1>The Go scheduler</h1></header>
+          <p>The scheduler of the Go runtime <b>multiplexes</b> goroutines onto threads.</p>
+          <h2>Work stealing</h2>
+          <p>Idle processors steal work from the queues of busy ones.</p>
+          <div class="share-buttons">Share on social media</div>
+          <script>trackPageView()</script>
+          <p hidden>Hidden text</p>
+        </article>
+      </main>
+      <aside><h3>Related posts</h3></aside>
+    </div>
+    <footer>Copyright</footer>
+  </body>
+</html>`
+
+func TestParsePageWithOptions_AnalyzesContent(t *testing.T) {
+	result, err := core.ParsePageWithOptions(strings.NewReader(articleHTML), "", core.ParseOptions{StoreText: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &models.Content{
+		Text: "The Go scheduler\n" +
+			"The scheduler of the Go runtime multiplexes goroutines onto threads.\n" +
+			"Work stealing\n" +
+			"Idle processors steal work from the queues of busy ones.",
+		WordCount:          25,
+		ReadingTimeSeconds: 8,
+		Headings: []models.Heading{
+			{Level: 1, Text: "The Go scheduler"},
+			{Level: 2, Text: "Work stealing"},
+		},
+		Language: "en",
+	}
+	if !reflect.DeepEqual(result.Content, expected) {
+		t.Errorf("expected content %+v, got %+v", expected, result.Content)
+	}
+}
+
+func TestParsePage_OmitsContentText(t *testing.T) {
+	result, err := core.ParsePage(strings.NewReader(articleHTML), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content == nil || result.Content.Text != "" || result.Content.WordCount != 25 {
+		t.Errorf("expected statistics without text, got %+v", result.Content)
+	}
+}
+
+func TestParsePage_ContentFallsBackToBody(t *testing.T) {
+	htmlData := `<html><body>
+	  <nav>Menu</nav>
+	  <article><h2>Erster Beitrag</h2><p>Das ist der erste Beitrag und er ist nicht lang.</p></article>
+	  <article><h2>Zweiter Beitrag</h2><p>Auch der zweite Beitrag ist kurz, aber wir lesen ihn.</p></article>
+	</body></html>`
+
+	result, err := core.ParsePageWithOptions(strings.NewReader(htmlData), "", core.ParseOptions{StoreText: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := result.Content
+	if content == nil || strings.Contains(content.Text, "Menu") || !strings.Contains(content.Text, "Zweiter Beitrag") {
+		t.Fatalf("expected the text of both articles without navigation, got %+v", content)
+	}
+	if len(content.Headings) != 2 || content.Language != "de" {
+		t.Errorf("unexpected headings or language: %+v", content)
+	}
+}
+
+func TestParsePage_UndetectedLanguage(t *testing.T) {
+	result, err := core.ParsePage(strings.NewReader(`<html><body><p>Widget 42</p></body></html>`), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content.Language != "" || result.Content.WordCount != 2 || result.Content.ReadingTimeSeconds != 1 {
+		t.Errorf("unexpected content %+v", result.Content)
+	}
+}
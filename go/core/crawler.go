@@ -0,0 +1,284 @@
+package core
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go-scraper/models"
+	"go-scraper/ui"
+)
+
+// CrawlOptions controls how far RunCrawl follows links discovered on scraped pages.
+type CrawlOptions struct {
+	MaxDepth       int      // Maximum link depth from a seed URL (0 = seeds only)
+	MaxPages       int      // Maximum number of pages to scrape in total (<= 0 = unlimited)
+	SameHost       bool     // Only follow links to the hosts of the seed URLs
+	AllowedDomains []string // Additional domains (including subdomains) that may be followed
+	Concurrency    int      // Number of concurrent workers per crawl level
+}
+
+// crawlTarget is a queued target together with the information needed to record its origin.
+type crawlTarget struct {
+	target models.Target
+	depth  int
+	parent string
+}
+
+// RunCrawl scrapes the seed URLs and recursively follows the links found on them.
+// The crawl proceeds breadth-first: all pages of one depth are scraped concurrently
+// (up to opts.Concurrency workers) before the links discovered on them are queued.
+// Every URL is visited at most once, links outside the configured scope are ignored,
+// and the crawl stops once opts.MaxDepth or opts.MaxPages is reached.
+// Context cancellation is respected - no further pages are started once ctx is cancelled
+// or a graceful stop is requested (see WithGracefulStop).
+// Each returned Page records its Depth and the ParentURL that discovered it.
+// Discovered pages inherit the options of their seed (headers, tags, timeout and
+// extraction rules) but are always requested with GET.
+// Run options can stream each page to a ResultSink as it completes (see WithSink).
+func RunCrawl(ctx context.Context, seeds []models.Target, scraper Scraper, opts CrawlOptions, runOpts ...RunOption) []*models.Page {
+	options := newRunOptions(runOpts)
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.MaxDepth < 0 {
+		opts.MaxDepth = 0
+	}
+
+	scope := newCrawlScope(models.TargetURLs(seeds), opts)
+	visited := make(map[string]struct{})
+
+	// Seed the frontier with the configured URLs (deduplicated)
+	frontier := make([]crawlTarget, 0, len(seeds))
+	for _, seed := range seeds {
+		key := seed.URL
+		if normalized, ok := normalizeCrawlURL(seed.URL); ok {
+			key = normalized
+		}
+		if _, seen := visited[key]; seen {
+			continue
+		}
+		visited[key] = struct{}{}
+		frontier = append(frontier, crawlTarget{target: seed})
+	}
+
+	// The expected total starts with the seeds; trackers of discovered URLs extend it
+	pbm := ui.NewProgressBarManager(len(frontier))
+	defer pbm.StopRenderer()
+
+	var pages []*models.Page
+	scraped := 0
+	for depth := 0; len(frontier) > 0 && !options.stopped(ctx); depth++ {
+		// Never scrape more pages than the remaining budget allows
+		if opts.MaxPages > 0 {
+			if remaining := opts.MaxPages - scraped; len(frontier) > remaining {
+				frontier = frontier[:remaining]
+			}
+		}
+
+		levelPages, levelTargets := crawlLevel(ctx, pbm, frontier, scraper, opts.Concurrency, options)
+		scraped += len(levelPages)
+
+		// A page reached through a redirect is visited under its final URL as well,
+		// and a redirected seed brings its final host into scope (e.g. www.example.com)
+		for _, page := range levelPages {
+			if page.Response == nil {
+				continue
+			}
+			if finalURL, ok := normalizeCrawlURL(page.Response.FinalURL); ok {
+				visited[finalURL] = struct{}{}
+				if depth == 0 {
+					scope.addSeed(finalURL)
+				}
+			}
+		}
+		if !options.discard {
+			pages = append(pages, levelPages...)
+		}
+
+		if depth >= opts.MaxDepth || (opts.MaxPages > 0 && scraped >= opts.MaxPages) {
+			break
+		}
+
+		// Queue all unvisited in-scope links for the next level
+		var next []crawlTarget
+		for i, page := range levelPages {
+			if !page.Success() {
+				continue
+			}
+			for _, link := range page.Links {
+				linkURL, ok := resolveCrawlLink(page.URL, link)
+				if !ok || !scope.allows(linkURL) {
+					continue
+				}
+				if _, seen := visited[linkURL]; seen {
+					continue
+				}
+				visited[linkURL] = struct{}{}
+
+				target := levelTargets[i]
+				target.URL = linkURL
+				target.Method = ""
+				next = append(next, crawlTarget{target: target, depth: depth + 1, parent: page.URL})
+			}
+		}
+		frontier = next
+	}
+
+	return pages
+}
+
+// crawlLevel scrapes all targets of a single crawl depth using a worker pool.
+// Results keep the order of targets; targets skipped due to cancellation are omitted.
+// The second slice holds the target each returned page was scraped from.
+func crawlLevel(ctx context.Context, pbm *ui.ProgressBarManager, targets []crawlTarget, scraper Scraper, concurrency int, options *runOptions) ([]*models.Page, []models.Target) {
+	results := make([]*models.Page, len(targets))
+	jobs := make(chan int, len(targets))
+	emitter := newOrderedEmitter(options, concurrency)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				emitter.wait(i)
+				if options.stopped(ctx) {
+					emitter.emit(i, nil) // keep later pages flowing to the sink
+					continue             // skip remaining targets if canceled
+				}
+
+				target := targets[i]
+				tracker := pbm.NewTracker(target.target.URL, 2)
+				tracker.Increment(1)
+
+				page, err := options.scrape(ctx, scraper, target.target)
+				if err != nil {
+					tracker.MarkAsErrored()
+				}
+				if page == nil {
+					page = failedPage(target.target, err)
+				}
+				page.Depth = target.depth
+				page.ParentURL = target.parent
+
+				tracker.Increment(1)
+				emitter.emit(i, page)
+				results[i] = page
+			}
+		}()
+	}
+
+	for i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	pages := make([]*models.Page, 0, len(results))
+	scrapedTargets := make([]models.Target, 0, len(results))
+	for i, page := range results {
+		if page != nil {
+			pages = append(pages, page)
+			scrapedTargets = append(scrapedTargets, targets[i].target)
+		}
+	}
+	return pages, scrapedTargets
+}
+
+// crawlScope decides which discovered URLs may be followed.
+type crawlScope struct {
+	hosts    map[string]struct{}
+	domains  []string
+	limited  bool
+	sameHost bool
+}
+
+// newCrawlScope builds the scope from the seed hosts (if SameHost is set)
+// and the allowed domains. Without any restriction every host is in scope.
+func newCrawlScope(seeds []string, opts CrawlOptions) *crawlScope {
+	scope := &crawlScope{hosts: make(map[string]struct{})}
+
+	if opts.SameHost {
+		scope.limited = true
+		scope.sameHost = true
+		for _, seed := range seeds {
+			scope.addSeed(seed)
+		}
+	}
+
+	for _, domain := range opts.AllowedDomains {
+		domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain != "" {
+			scope.limited = true
+			scope.domains = append(scope.domains, domain)
+		}
+	}
+
+	return scope
+}
+
+// addSeed adds the host of a seed URL to the scope if it is limited to the seed hosts.
+func (s *crawlScope) addSeed(seed string) {
+	if !s.sameHost {
+		return
+	}
+	if u, err := url.Parse(seed); err == nil && u.Host != "" {
+		s.hosts[strings.ToLower(u.Hostname())] = struct{}{}
+	}
+}
+
+// allows reports whether the absolute URL lies within the crawl scope.
+func (s *crawlScope) allows(rawURL string) bool {
+	if !s.limited {
+		return true
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+
+	if _, ok := s.hosts[host]; ok {
+		return true
+	}
+	for _, domain := range s.domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveCrawlLink resolves a link against the URL of the page it was found on
+// and normalizes it. Returns false for links that cannot be crawled (e.g. mailto:).
+func resolveCrawlLink(pageURL, link string) (string, bool) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return "", false
+	}
+	ref, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", false
+	}
+	return normalizeCrawlURL(base.ResolveReference(ref).String())
+}
+
+// normalizeCrawlURL returns the canonical form used for visited-set deduplication:
+// only absolute http(s) URLs are accepted, the host is lowercased and fragments are removed.
+func normalizeCrawlURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", false
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String(), true
+}
@@ -0,0 +1,172 @@
+package core_test
+
+import (
+	"context"
+	"go-scraper/core"
+	"go-scraper/models"
+	"sync"
+	"testing"
+)
+
+// graphScraper serves pages from an in-memory link graph.
+type graphScraper struct {
+	mu      sync.Mutex
+	links   map[string][]string
+	scraped []string
+}
+
+func (g *graphScraper) Scrape(_ context.Context, target models.Target) (*models.Page, error) {
+	g.mu.Lock()
+	g.scraped = append(g.scraped, target.URL)
+	g.mu.Unlock()
+	return &models.Page{URL: target.URL, Links: g.links[target.URL], Tags: target.Tags}, nil
+}
+
+func newGraphScraper() *graphScraper {
+	return &graphScraper{links: map[string][]string{
+		"https://a.com/":     {"/one", "https://a.com/two#section", "mailto:me@a.com", "https://b.com/"},
+		"https://a.com/one":  {"/two", "/three", "https://a.com/"},
+		"https://a.com/two":  {"/three"},
+		"https://a.com/four": {},
+		"https://b.com/":     {"https://b.com/x"},
+	}}
+}
+
+func pagesByURL(pages []*models.Page) map[string]*models.Page {
+	byURL := make(map[string]*models.Page, len(pages))
+	for _, p := range pages {
+		byURL[p.URL] = p
+	}
+	return byURL
+}
+
+func TestRunCrawl_FollowsLinksWithinDepth(t *testing.T) {
+	scraper := newGraphScraper()
+	pages := core.RunCrawl(context.Background(), models.NewTargets("https://a.com/"), scraper, core.CrawlOptions{
+		MaxDepth:    1,
+		MaxPages:    100,
+		SameHost:    true,
+		Concurrency: 2,
+	})
+
+	byURL := pagesByURL(pages)
+	if len(pages) != 3 {
+		t.Fatalf("expected 3 pages (seed + 2 links), got %d: %v", len(pages), scraper.scraped)
+	}
+	if _, ok := byURL["https://b.com/"]; ok {
+		t.Error("expected off-host link to be skipped")
+	}
+	if _, ok := byURL["https://a.com/three"]; ok {
+		t.Error("expected depth-2 link to be skipped")
+	}
+
+	one := byURL["https://a.com/one"]
+	if one == nil || one.Depth != 1 || one.ParentURL != "https://a.com/" {
+		t.Errorf("unexpected depth/parent for /one: %#v", one)
+	}
+	if seed := byURL["https://a.com/"]; seed == nil || seed.Depth != 0 || seed.ParentURL != "" {
+		t.Errorf("unexpected depth/parent for seed: %#v", seed)
+	}
+}
+
+func TestRunCrawl_VisitsEachURLOnce(t *testing.T) {
+	scraper := newGraphScraper()
+	core.RunCrawl(context.Background(), models.NewTargets("https://a.com/", "https://a.com"), scraper, core.CrawlOptions{
+		MaxDepth:    5,
+		MaxPages:    100,
+		SameHost:    true,
+		Concurrency: 3,
+	})
+
+	seen := make(map[string]int)
+	for _, u := range scraper.scraped {
+		seen[u]++
+		if seen[u] > 1 {
+			t.Errorf("URL %s scraped more than once", u)
+		}
+	}
+	if len(scraper.scraped) != 4 {
+		t.Errorf("expected 4 unique pages, got %d: %v", len(scraper.scraped), scraper.scraped)
+	}
+}
+
+func TestRunCrawl_MaxPages(t *testing.T) {
+	scraper := newGraphScraper()
+	pages := core.RunCrawl(context.Background(), models.NewTargets("https://a.com/"), scraper, core.CrawlOptions{
+		MaxDepth:    5,
+		MaxPages:    2,
+		Concurrency: 1,
+	})
+
+	if len(pages) != 2 {
+		t.Errorf("expected crawl to stop after 2 pages, got %d", len(pages))
+	}
+}
+
+func TestRunCrawl_AllowedDomains(t *testing.T) {
+	scraper := newGraphScraper()
+	pages := core.RunCrawl(context.Background(), models.NewTargets("https://a.com/"), scraper, core.CrawlOptions{
+		MaxDepth:       1,
+		MaxPages:       100,
+		AllowedDomains: []string{"b.com"},
+		Concurrency:    1,
+	})
+
+	byURL := pagesByURL(pages)
+	if _, ok := byURL["https://b.com/"]; !ok {
+		t.Error("expected allowed domain to be followed")
+	}
+	if _, ok := byURL["https://a.com/one"]; ok {
+		t.Error("expected seed host to be out of scope when only other domains are allowed")
+	}
+}
+
+func TestRunCrawl_InheritsSeedOptions(t *testing.T) {
+	seed := models.Target{URL: "https://a.com/", Method: "POST", Tags: []string{"docs"}}
+	pages := core.RunCrawl(context.Background(), []models.Target{seed}, newGraphScraper(), core.CrawlOptions{
+		MaxDepth:    1,
+		SameHost:    true,
+		Concurrency: 1,
+	})
+
+	if len(pages) != 3 {
+		t.Fatalf("expected 3 pages, got %d", len(pages))
+	}
+	for _, page := range pages {
+		if len(page.Tags) != 1 || page.Tags[0] != "docs" {
+			t.Errorf("expected %s to inherit the seed tags, got %v", page.URL, page.Tags)
+		}
+	}
+}
+
+// redirectScraper serves a seed that redirects to another host.
+type redirectScraper struct {
+	graphScraper
+}
+
+func (r *redirectScraper) Scrape(ctx context.Context, target models.Target) (*models.Page, error) {
+	page, err := r.graphScraper.Scrape(ctx, target)
+	if target.URL == "https://example.com/" {
+		page.Response = &models.ResponseInfo{FinalURL: "https://www.example.com/"}
+		page.Links = []string{"https://www.example.com/", "https://www.example.com/about", "https://other.com/"}
+	}
+	return page, err
+}
+
+func TestRunCrawl_FollowsRedirectedSeedHost(t *testing.T) {
+	scraper := &redirectScraper{graphScraper{links: map[string][]string{}}}
+	pages := core.RunCrawl(context.Background(), models.NewTargets("https://example.com/"), scraper, core.CrawlOptions{
+		MaxDepth:    2,
+		MaxPages:    100,
+		SameHost:    true,
+		Concurrency: 1,
+	})
+
+	byURL := pagesByURL(pages)
+	if len(pages) != 2 || byURL["https://www.example.com/about"] == nil {
+		t.Errorf("expected the seed and the link on its final host, got %v", scraper.scraped)
+	}
+	if byURL["https://www.example.com/"] != nil {
+		t.Error("expected the final URL of the seed not to be scraped again")
+	}
+}
@@ -0,0 +1,168 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"syscall"
+
+	"go-scraper/models"
+)
+
+// Sentinel errors for each failure category. Errors returned by Scraper.Scrape
+// match the sentinel of their category with errors.Is, e.g.
+//
+//	if errors.Is(err, core.ErrTimeout) { ... }
+//
+// ErrDisallowedByRobots covers pages excluded by robots.txt.
+var (
+	ErrTimeout           = errors.New("timeout")
+	ErrDNS               = errors.New("DNS lookup failed")
+	ErrConnectionRefused = errors.New("connection refused")
+	ErrTLS               = errors.New("TLS error")
+	ErrHTTPStatus        = errors.New("unexpected HTTP status")
+	ErrParse             = errors.New("HTML parse error")
+	ErrCancelled         = errors.New("cancelled")
+	ErrDeadline          = errors.New("per-URL deadline exceeded")
+)
+
+// ScrapeError is the error returned by DefaultScraper.Scrape. It carries the
+// classified ErrorKind and, for HTTP status failures, the status code. Use
+// errors.As to access it, or errors.Is with the sentinel errors above.
+type ScrapeError struct {
+	Kind       models.ErrorKind // Failure category
+	URL        string           // URL that was scraped
+	StatusCode int              // HTTP status code (0 if no response was received)
+	Err        error            // Underlying cause
+}
+
+// Error implements the error interface.
+func (e *ScrapeError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying cause.
+func (e *ScrapeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of the ScrapeError's kind.
+func (e *ScrapeError) Is(target error) bool {
+	sentinel := kindSentinel(e.Kind)
+	return sentinel != nil && target == sentinel
+}
+
+// Is makes HTTP status errors match ErrHTTPStatus.
+func (e *HTTPStatusError) Is(target error) bool {
+	return target == ErrHTTPStatus
+}
+
+// kindSentinel maps an ErrorKind to its sentinel error.
+func kindSentinel(kind models.ErrorKind) error {
+	switch kind {
+	case models.ErrorKindTimeout:
+		return ErrTimeout
+	case models.ErrorKindDNS:
+		return ErrDNS
+	case models.ErrorKindConnectionRefused:
+		return ErrConnectionRefused
+	case models.ErrorKindTLS:
+		return ErrTLS
+	case models.ErrorKindHTTPStatus:
+		return ErrHTTPStatus
+	case models.ErrorKindParse:
+		return ErrParse
+	case models.ErrorKindRobotsDisallowed:
+		return ErrDisallowedByRobots
+	case models.ErrorKindCancelled:
+		return ErrCancelled
+	case models.ErrorKindDeadline:
+		return ErrDeadline
+	default:
+		return nil
+	}
+}
+
+// ClassifyError determines the ErrorKind of a fetch error.
+// Returns an empty kind for nil errors.
+func ClassifyError(err error) models.ErrorKind {
+	if err == nil {
+		return ""
+	}
+
+	// Already classified (e.g. returned by Scrape)
+	var scrapeErr *ScrapeError
+	if errors.As(err, &scrapeErr) {
+		return scrapeErr.Kind
+	}
+
+	if errors.Is(err, ErrHTTPStatus) {
+		return models.ErrorKindHTTPStatus
+	}
+	if errors.Is(err, ErrParse) {
+		return models.ErrorKindParse
+	}
+	if errors.Is(err, context.Canceled) {
+		return models.ErrorKindCancelled
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return models.ErrorKindDNS
+	}
+	if isTLSError(err) {
+		return models.ErrorKindTLS
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return models.ErrorKindConnectionRefused
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return models.ErrorKindTimeout
+	}
+
+	// Checked after the network causes, so a wrapped network error is never hidden
+	if errors.Is(err, ErrDisallowedByRobots) {
+		return models.ErrorKindRobotsDisallowed
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) || errors.As(err, &netErr) {
+		return models.ErrorKindNetwork
+	}
+
+	return models.ErrorKindUnknown
+}
+
+// isTLSError reports whether err stems from the TLS handshake or certificate verification.
+func isTLSError(err error) bool {
+	var (
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	return errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
+
+// newScrapeError wraps err in a ScrapeError with the given kind.
+// The HTTP status code is extracted from HTTPStatusError causes.
+func newScrapeError(kind models.ErrorKind, url string, err error) *ScrapeError {
+	scrapeErr := &ScrapeError{Kind: kind, URL: url, Err: err}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		scrapeErr.StatusCode = statusErr.StatusCode
+	}
+	return scrapeErr
+}
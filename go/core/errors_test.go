@@ -0,0 +1,109 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"go-scraper/core"
+	"go-scraper/models"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected models.ErrorKind
+	}{
+		{"Nil", nil, ""},
+		{"HTTPStatus", &core.HTTPStatusError{URL: "https://a.com", StatusCode: 404}, models.ErrorKindHTTPStatus},
+		{"DNS", &net.DNSError{Err: "no such host", Name: "nope.invalid", IsNotFound: true}, models.ErrorKindDNS},
+		{"Deadline", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), models.ErrorKindTimeout},
+		{"Cancelled", fmt.Errorf("wrapped: %w", context.Canceled), models.ErrorKindCancelled},
+		{"Robots", fmt.Errorf("%w: https://a.com/x", core.ErrDisallowedByRobots), models.ErrorKindRobotsDisallowed},
+		{"RobotsWrappingNetwork", fmt.Errorf("%w: %w", core.ErrDisallowedByRobots, syscall.ECONNREFUSED), models.ErrorKindConnectionRefused},
+		{"Unknown", errors.New("something odd"), models.ErrorKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := core.ClassifyError(tt.err); got != tt.expected {
+				t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClassifyError_RealFailures(t *testing.T) {
+	t.Run("ConnectionRefused", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		addr := server.URL
+		server.Close()
+
+		_, err := core.NewFetcher(2*time.Second, "UserAgent").Fetch(context.Background(), addr)
+		if kind := core.ClassifyError(err); kind != models.ErrorKindConnectionRefused {
+			t.Errorf("expected %q, got %q (%v)", models.ErrorKindConnectionRefused, kind, err)
+		}
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.NotFoundHandler())
+		defer server.Close()
+
+		_, err := core.NewFetcher(2*time.Second, "UserAgent").Fetch(context.Background(), server.URL)
+		if kind := core.ClassifyError(err); kind != models.ErrorKindTLS {
+			t.Errorf("expected %q, got %q (%v)", models.ErrorKindTLS, kind, err)
+		}
+	})
+
+	t.Run("ClientTimeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		_, err := core.NewFetcher(20*time.Millisecond, "UserAgent").Fetch(context.Background(), server.URL)
+		if kind := core.ClassifyError(err); kind != models.ErrorKindTimeout {
+			t.Errorf("expected %q, got %q (%v)", models.ErrorKindTimeout, kind, err)
+		}
+	})
+}
+
+func TestScraper_Scrape_TypedErrors(t *testing.T) {
+	s := core.NewScraper(&MockFetcher{Err: &core.HTTPStatusError{URL: "https://a.com", StatusCode: 503}})
+
+	page, err := s.Scrape(context.Background(), models.Target{URL: "https://a.com"})
+	if !errors.Is(err, core.ErrHTTPStatus) {
+		t.Fatalf("expected errors.Is(err, ErrHTTPStatus), got %v", err)
+	}
+	if errors.Is(err, core.ErrTimeout) {
+		t.Error("expected HTTP status error not to match ErrTimeout")
+	}
+
+	var scrapeErr *core.ScrapeError
+	if !errors.As(err, &scrapeErr) || scrapeErr.StatusCode != 503 {
+		t.Fatalf("expected *ScrapeError with status 503, got %#v", err)
+	}
+
+	if page.ErrorKind != models.ErrorKindHTTPStatus || page.HTTPStatus != 503 {
+		t.Errorf("unexpected page classification: kind=%q status=%d", page.ErrorKind, page.HTTPStatus)
+	}
+}
+
+func TestScraper_Scrape_TimeoutSentinel(t *testing.T) {
+	s := core.NewScraper(&MockFetcher{Err: fmt.Errorf("request: %w", context.DeadlineExceeded)})
+
+	page, err := s.Scrape(context.Background(), models.Target{URL: "https://a.com"})
+	if !errors.Is(err, core.ErrTimeout) {
+		t.Fatalf("expected errors.Is(err, ErrTimeout), got %v", err)
+	}
+	if page.ErrorKind != models.ErrorKindTimeout {
+		t.Errorf("expected error kind %q, got %q", models.ErrorKindTimeout, page.ErrorKind)
+	}
+}
@@ -0,0 +1,238 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+
+	"go-scraper/models"
+)
+
+// FieldExtractor evaluates named extraction rules against a parsed HTML document.
+// Rules are compiled once, so an extractor can be shared by concurrent scrapes.
+type FieldExtractor struct {
+	rules  map[string]models.ExtractRule
+	fields []compiledField // sorted by name for deterministic evaluation
+}
+
+// compiledField is an extraction rule with its compiled CSS selector or XPath expression.
+type compiledField struct {
+	name     string
+	rule     models.ExtractRule
+	selector cascadia.Selector // nil for XPath rules
+	xpaths   *sync.Pool        // *xpath.Expr instances for XPath rules, as they keep state while evaluated
+}
+
+// NewFieldExtractor compiles the rules. It returns nil (no extraction) for an empty
+// rule set and an error naming the field if a selector or XPath expression is invalid.
+func NewFieldExtractor(rules map[string]models.ExtractRule) (*FieldExtractor, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	e := &FieldExtractor{rules: rules, fields: make([]compiledField, 0, len(rules))}
+	for _, name := range slices.Sorted(maps.Keys(rules)) {
+		field, err := compileField(name, rules[name])
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", name, err)
+		}
+		e.fields = append(e.fields, field)
+	}
+	return e, nil
+}
+
+// compileField compiles the selector or XPath expression of a rule; exactly one must be set.
+func compileField(name string, rule models.ExtractRule) (compiledField, error) {
+	field := compiledField{name: name, rule: rule}
+	hasSelector, hasXPath := strings.TrimSpace(rule.Selector) != "", strings.TrimSpace(rule.XPath) != ""
+	switch {
+	case hasSelector && hasXPath:
+		return field, errors.New("selector and xpath are mutually exclusive")
+	case hasSelector:
+		selector, err := cascadia.Compile(rule.Selector)
+		if err != nil {
+			return field, fmt.Errorf("invalid selector %q: %w", rule.Selector, err)
+		}
+		field.selector = selector
+	case hasXPath:
+		expr, err := xpath.Compile(rule.XPath)
+		if err != nil {
+			return field, fmt.Errorf("invalid xpath %q: %w", rule.XPath, err)
+		}
+		field.xpaths = &sync.Pool{New: func() any { return xpath.MustCompile(rule.XPath) }}
+		field.xpaths.Put(expr)
+	default:
+		return field, errors.New("selector or xpath is required")
+	}
+	return field, nil
+}
+
+// With returns an extractor that evaluates the receiver's rules plus extra ones;
+// extra rules replace rules of the same name. The receiver may be nil.
+func (e *FieldExtractor) With(extra map[string]models.ExtractRule) (*FieldExtractor, error) {
+	if len(extra) == 0 {
+		return e, nil
+	}
+	merged := make(map[string]models.ExtractRule, len(extra))
+	if e != nil {
+		maps.Copy(merged, e.rules)
+	}
+	maps.Copy(merged, extra)
+	return NewFieldExtractor(merged)
+}
+
+// extract evaluates all rules against doc. Single-value fields hold the first match
+// as a string, multi-value fields a list of all matches; fields without a match are
+// omitted. href and src attribute values are resolved to absolute URLs.
+func (e *FieldExtractor) extract(doc *html.Node, resolver *urlResolver) map[string]any {
+	if e == nil {
+		return nil
+	}
+
+	fields := make(map[string]any, len(e.fields))
+	for _, field := range e.fields {
+		values := field.values(doc, resolver)
+		switch {
+		case len(values) == 0:
+		case field.rule.Multiple:
+			fields[field.name] = values
+		default:
+			fields[field.name] = values[0]
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// values returns the non-empty values of all matches in document order.
+func (f compiledField) values(doc *html.Node, resolver *urlResolver) []string {
+	if f.xpaths == nil {
+		var values []string
+		for _, n := range f.selector.MatchAll(doc) {
+			if value, ok := f.value(n, resolver); ok {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+	return f.xpathValues(doc, resolver)
+}
+
+// xpathValues evaluates the XPath expression. Selected elements are read like CSS
+// matches, attribute and text nodes by their value; expressions computing a string,
+// number or boolean (such as count(//a)) yield a single value.
+func (f compiledField) xpathValues(doc *html.Node, resolver *urlResolver) []string {
+	expr := f.xpaths.Get().(*xpath.Expr)
+	defer f.xpaths.Put(expr)
+
+	var value string
+	switch result := expr.Evaluate(htmlquery.CreateXPathNavigator(doc)).(type) {
+	case *xpath.NodeIterator:
+		var values []string
+		for result.MoveNext() {
+			nav, ok := result.Current().(*htmlquery.NodeNavigator)
+			if !ok {
+				continue
+			}
+			var value string
+			var found bool
+			switch {
+			case nav.NodeType() == xpath.AttributeNode:
+				value, found = attributeValue(nav.LocalName(), nav.Value(), resolver)
+			case nav.Current().Type == html.TextNode:
+				value = strings.Join(strings.Fields(nav.Current().Data), " ")
+				found = value != ""
+			default:
+				value, found = f.value(nav.Current(), resolver)
+			}
+			if found {
+				values = append(values, value)
+			}
+		}
+		return values
+	case string:
+		value = strings.TrimSpace(result)
+	case float64:
+		value = strconv.FormatFloat(result, 'f', -1, 64)
+	case bool:
+		value = strconv.FormatBool(result)
+	}
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// value reads the text or the configured attribute of n. Elements without the
+// attribute or with empty text do not count as a match.
+func (f compiledField) value(n *html.Node, resolver *urlResolver) (string, bool) {
+	if f.rule.Attr == "" {
+		text := nodeText(n)
+		return text, text != ""
+	}
+
+	for _, attr := range n.Attr {
+		if strings.EqualFold(attr.Key, f.rule.Attr) {
+			return attributeValue(attr.Key, attr.Val, resolver)
+		}
+	}
+	return "", false
+}
+
+// attributeValue trims an attribute value and resolves href and src values to
+// absolute URLs. Empty values do not count as a match.
+func attributeValue(key, value string, resolver *urlResolver) (string, bool) {
+	value = strings.TrimSpace(value)
+	if key = strings.ToLower(key); (key == "href" || key == "src") && value != "" {
+		if resolved, ok := resolver.resolve(value); ok {
+			value = resolved.url
+		}
+	}
+	return value, value != ""
+}
+
+// blockElements separate their text from the surrounding text in nodeText.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true, "dd": true,
+	"div": true, "dl": true, "dt": true, "figcaption": true, "footer": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true, "li": true,
+	"main": true, "nav": true, "ol": true, "p": true, "pre": true, "section": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+}
+
+// nodeText returns the text content of n and its descendants with whitespace collapsed.
+// Inline elements are joined without a separator (so "$<b>19</b>.99" stays "$19.99"),
+// block elements are separated by a space, and <script> and <style> content is skipped.
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			sb.WriteString(n.Data)
+			return
+		case n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style"):
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+		if n.Type == html.ElementNode && blockElements[n.Data] {
+			sb.WriteByte(' ')
+		}
+	}
+	collect(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,155 @@
+package core_test
+
+import (
+	"go-scraper/core"
+	"go-scraper/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const productHTML = `
+<html>
+  <head><meta name="author" content=" Jane Doe "></head>
+  <body>
+    <h1 class="name">Blue <em>Widget</em></h1>
+    <p class="price">$<b>19</b>.99</p>
+    <ul class="features"><li>Small</li><li></li><li>Light</li></ul>
+    <div class="desc"><p>First.</p><p>Second.</p><script>var x = 1;</script></div>
+    <a class="manual" href="/docs/manual.pdf#page=2">Manual</a>
+  </body>
+</html>`
+
+func TestParsePageWithOptions_ExtractsFields(t *testing.T) {
+	fields, err := core.NewFieldExtractor(map[string]models.ExtractRule{
+		"name":     {Selector: "h1.name"},
+		"price":    {Selector: ".price"},
+		"author":   {Selector: "meta[name=author]", Attr: "content"},
+		"features": {Selector: ".features li", Multiple: true},
+		"desc":     {Selector: ".desc"},
+		"manual":   {Selector: "a.manual", Attr: "HREF"},
+		"missing":  {Selector: ".sku"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := core.ParsePageWithOptions(strings.NewReader(productHTML), "https://shop.com/p/1", core.ParseOptions{Fields: fields})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]any{
+		"name":     "Blue Widget",
+		"price":    "$19.99",
+		"author":   "Jane Doe",
+		"features": []string{"Small", "Light"},
+		"desc":     "First. Second.",
+		"manual":   "https://shop.com/docs/manual.pdf",
+	}
+	if !reflect.DeepEqual(result.Fields, expected) {
+		t.Errorf("expected fields %v, got %v", expected, result.Fields)
+	}
+}
+
+func TestParsePageWithOptions_ExtractsXPathFields(t *testing.T) {
+	htmlData := `
+	<html><body>
+	  <dl><dt>SKU</dt><dd> W-42 </dd><dt>Weight</dt><dd>1 kg</dd></dl>
+	  <ul class="features"><li>Small</li><li>Light</li></ul>
+	  <a class="manual" href="/docs/manual.pdf">Manual</a>
+	</body></html>`
+
+	fields, err := core.NewFieldExtractor(map[string]models.ExtractRule{
+		"sku":      {XPath: "//dt[text()='SKU']/following-sibling::dd[1]"},
+		"features": {XPath: "//ul[@class='features']/li/text()", Multiple: true},
+		"manual":   {XPath: "//a[@class='manual']/@href"},
+		"manualEl": {XPath: "//a[@class='manual']", Attr: "href"},
+		"links":    {XPath: "count(//a)"},
+		"title":    {XPath: "string(//title)"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := core.ParsePageWithOptions(strings.NewReader(htmlData), "https://shop.com/p/1", core.ParseOptions{Fields: fields})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]any{
+		"sku":      "W-42",
+		"features": []string{"Small", "Light"},
+		"manual":   "https://shop.com/docs/manual.pdf",
+		"manualEl": "https://shop.com/docs/manual.pdf",
+		"links":    "1",
+	}
+	if !reflect.DeepEqual(result.Fields, expected) {
+		t.Errorf("expected fields %v, got %v", expected, result.Fields)
+	}
+}
+
+func TestParsePageWithOptions_NoMatches(t *testing.T) {
+	fields, err := core.NewFieldExtractor(map[string]models.ExtractRule{"sku": {Selector: ".sku"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := core.ParsePageWithOptions(strings.NewReader(productHTML), "", core.ParseOptions{Fields: fields})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Fields != nil {
+		t.Errorf("expected no fields, got %v", result.Fields)
+	}
+}
+
+func TestNewFieldExtractor_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules map[string]models.ExtractRule
+		want  string
+	}{
+		{"MissingSelector", map[string]models.ExtractRule{"price": {Attr: "content"}}, `field "price": selector or xpath is required`},
+		{"SelectorAndXPath", map[string]models.ExtractRule{"price": {Selector: ".price", XPath: "//p"}}, `field "price": selector and xpath are mutually exclusive`},
+		{"InvalidSelector", map[string]models.ExtractRule{"price": {Selector: "div["}}, `field "price": invalid selector "div["`},
+		{"InvalidXPath", map[string]models.ExtractRule{"price": {XPath: "//p[@class="}}, `field "price": invalid xpath "//p[@class="`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := core.NewFieldExtractor(tt.rules)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestFieldExtractor_With(t *testing.T) {
+	base, err := core.NewFieldExtractor(map[string]models.ExtractRule{
+		"name":  {Selector: "h1.name"},
+		"price": {Selector: ".missing"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	merged, err := base.With(map[string]models.ExtractRule{"price": {Selector: ".price"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := core.ParsePageWithOptions(strings.NewReader(productHTML), "", core.ParseOptions{Fields: merged})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]any{"name": "Blue Widget", "price": "$19.99"}
+	if !reflect.DeepEqual(result.Fields, expected) {
+		t.Errorf("expected fields %v, got %v", expected, result.Fields)
+	}
+
+	var none *core.FieldExtractor
+	if _, err := none.With(map[string]models.ExtractRule{"bad": {Selector: "["}}); err == nil {
+		t.Error("expected error for invalid extra rule")
+	}
+}
@@ -2,10 +2,16 @@ package core
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/http/httptrace"
+	"sync"
 	"time"
+
+	"go-scraper/models"
 )
 
 // HTTPFetcher defines an interface for fetching remote content over HTTP.
@@ -14,10 +20,65 @@ import (
 //
 // Implementations should:
 //   - Respect context cancellation and timeouts
-//   - Return the raw response body as bytes
+//   - Return the raw response body together with response metadata
 //   - Return an error if the request fails or status code is not 200 OK
+//   - Optionally return a non-nil result alongside an error to report partial
+//     information such as the response status or the number of attempts made
 type HTTPFetcher interface {
-	Fetch(ctx context.Context, url string) ([]byte, error)
+	Fetch(ctx context.Context, url string) (*FetchResult, error)
+}
+
+// FetchResult holds the outcome of an HTTP fetch.
+// For non-200 responses the metadata is filled in but Body is empty.
+type FetchResult struct {
+	Body          []byte        // Raw response body
+	FinalURL      string        // URL of the final response after following redirects
+	Attempts      int           // Number of requests made, including retries
+	StatusCode    int           // HTTP status code of the final response (0 if none was received)
+	Header        http.Header   // Headers of the final response
+	RedirectChain []string      // URLs that redirected to FinalURL, in request order
+	Timing        models.Timing // Durations of the request phases
+	FromCache     bool          // Served from a CachingFetcher instead of downloaded
+}
+
+// ResponseInfo converts the result into the page model representation,
+// keeping only the named headers (matched case-insensitively).
+func (r *FetchResult) ResponseInfo(headers []string) *models.ResponseInfo {
+	if r == nil || r.StatusCode == 0 {
+		return nil
+	}
+
+	info := &models.ResponseInfo{
+		StatusCode:    r.StatusCode,
+		FinalURL:      r.FinalURL,
+		RedirectChain: r.RedirectChain,
+		ContentType:   r.Header.Get("Content-Type"),
+		ContentLength: int64(len(r.Body)),
+		Timing:        r.Timing,
+	}
+
+	for _, name := range headers {
+		if value := r.Header.Get(name); value != "" {
+			if info.Headers == nil {
+				info.Headers = make(map[string]string)
+			}
+			info.Headers[http.CanonicalHeaderKey(name)] = value
+		}
+	}
+	return info
+}
+
+// HTTPStatusError is returned when a server responds with a status code other than 200 OK.
+// The response headers are kept so callers can inspect e.g. Retry-After.
+type HTTPStatusError struct {
+	URL        string      // Requested URL
+	StatusCode int         // HTTP status code of the response
+	Header     http.Header // Response headers
+}
+
+// Error implements the error interface.
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status for %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
 }
 
 // Fetcher is the production implementation of HTTPFetcher using the standard net/http client.
@@ -38,16 +99,54 @@ func NewFetcher(timeout time.Duration, userAgent string) *Fetcher {
 	}
 }
 
-// Fetch performs an HTTP GET request and returns the response body as bytes.
+// RequestOptions customizes the HTTP request for a single URL.
+// The options travel with the context (see WithRequestOptions), so they pass through
+// the fetcher decorators (rate limiting, retries, robots.txt) unchanged.
+type RequestOptions struct {
+	Method  string            // HTTP method; empty means GET
+	Headers map[string]string // Additional request headers; a User-Agent entry replaces the default
+}
+
+// requestOptionsKey is the context key for RequestOptions.
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a context that makes Fetcher apply opts to its requests.
+func WithRequestOptions(ctx context.Context, opts RequestOptions) context.Context {
+	return context.WithValue(ctx, requestOptionsKey{}, opts)
+}
+
+// requestOptionsFrom returns the RequestOptions stored in ctx (zero value if none).
+func requestOptionsFrom(ctx context.Context) RequestOptions {
+	opts, _ := ctx.Value(requestOptionsKey{}).(RequestOptions)
+	return opts
+}
+
+// Fetch performs an HTTP request (GET unless RequestOptions say otherwise) and returns the response body together with
+// the final URL after redirects (needed to resolve relative links) and response
+// metadata: status code, headers, redirect chain and phase timings via httptrace.
 // The context allows for cancellation and additional timeout control beyond the client timeout.
-// Returns an error if the request fails, times out, or receives a non-200 status code.
-func (f *Fetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// Returns an error if the request fails, times out, or receives a non-200 status code;
+// for non-200 responses the result carries the response metadata as well.
+func (f *Fetcher) Fetch(ctx context.Context, url string) (*FetchResult, error) {
+	trace := &requestTrace{}
+	ctx = httptrace.WithClientTrace(ctx, trace.clientTrace())
+
+	opts := requestOptionsFrom(ctx)
+	method := opts.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request for %s: %w", url, err)
 	}
 	req.Header.Set("User-Agent", f.UserAgent)
+	for name, value := range opts.Headers {
+		req.Header.Set(name, value)
+	}
 
+	trace.start = time.Now()
 	resp, err := f.Client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP request failed for %s: %w", url, err)
@@ -56,8 +155,17 @@ func (f *Fetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
 		_ = resp.Body.Close()
 	}()
 
+	result := &FetchResult{
+		FinalURL:      resp.Request.URL.String(),
+		Attempts:      1,
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		RedirectChain: redirectChain(resp),
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected HTTP status for %s: %d %s", url, resp.StatusCode, resp.Status)
+		result.Timing = trace.timing()
+		return result, &HTTPStatusError{URL: url, StatusCode: resp.StatusCode, Header: resp.Header}
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -65,5 +173,81 @@ func (f *Fetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to read response body from %s: %w", url, err)
 	}
 
-	return body, nil
+	result.Body = body
+	result.Timing = trace.timing()
+	return result, nil
+}
+
+// redirectChain returns the URLs of all requests that were redirected before
+// the final response, oldest first.
+func redirectChain(resp *http.Response) []string {
+	var chain []string
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		if prev := req.Response.Request; prev != nil {
+			chain = append([]string{prev.URL.String()}, chain...)
+		}
+	}
+	return chain
+}
+
+// requestTrace records the phase durations of a request through httptrace hooks.
+// Hooks may run on other goroutines, so access is synchronized. With redirects,
+// durations of all hops are summed up.
+type requestTrace struct {
+	mu                         sync.Mutex
+	start                      time.Time
+	dnsStart, connStart, tlsAt time.Time
+	dns, connect, tls          time.Duration
+	firstByte                  time.Duration
+}
+
+// clientTrace returns the httptrace hooks that feed this trace.
+func (t *requestTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { t.add(&t.dns, &t.dnsStart) },
+		ConnectStart:      func(string, string) { t.mark(&t.connStart) },
+		ConnectDone:       func(string, string, error) { t.add(&t.connect, &t.connStart) },
+		TLSHandshakeStart: func() { t.mark(&t.tlsAt) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.add(&t.tls, &t.tlsAt) },
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.firstByte = time.Since(t.start)
+		},
+	}
+}
+
+// mark records the current time in the given field.
+func (t *requestTrace) mark(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*field = time.Now()
+}
+
+// add accumulates the time elapsed since start into the given duration.
+func (t *requestTrace) add(total *time.Duration, start *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !start.IsZero() {
+		*total += time.Since(*start)
+	}
+}
+
+// timing converts the recorded durations into the model representation.
+func (t *requestTrace) timing() models.Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return models.Timing{
+		DNSMs:       milliseconds(t.dns),
+		ConnectMs:   milliseconds(t.connect),
+		TLSMs:       milliseconds(t.tls),
+		FirstByteMs: milliseconds(t.firstByte),
+		TotalMs:     milliseconds(time.Since(t.start)),
+	}
+}
+
+// milliseconds converts a duration to fractional milliseconds rounded to microseconds.
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
 }
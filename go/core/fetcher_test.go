@@ -26,13 +26,13 @@ func TestFetcher_Fetch_Success(t *testing.T) {
 	defer server.Close()
 
 	fetcher := core.NewFetcher(2*time.Second, "UserAgent")
-	body, err := fetcher.Fetch(context.Background(), server.URL)
+	result, err := fetcher.Fetch(context.Background(), server.URL)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if !strings.Contains(string(body), "Hello") {
-		t.Errorf("expected body to contain 'Hello', got %s", string(body))
+	if !strings.Contains(string(result.Body), "Hello") {
+		t.Errorf("expected body to contain 'Hello', got %s", string(result.Body))
 	}
 }
 
@@ -48,3 +48,82 @@ func TestFetcher_Fetch_StatusError(t *testing.T) {
 		t.Fatal("expected error for non-200 response")
 	}
 }
+
+func TestFetcher_Fetch_FinalURLAfterRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new/page", http.StatusMovedPermanently)
+			return
+		}
+		_, _ = io.WriteString(w, "<html></html>")
+	}))
+	defer server.Close()
+
+	fetcher := core.NewFetcher(2*time.Second, "UserAgent")
+	result, err := fetcher.Fetch(context.Background(), server.URL+"/old")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.FinalURL != server.URL+"/new/page" {
+		t.Errorf("expected final URL %s/new/page, got %s", server.URL, result.FinalURL)
+	}
+}
+
+func TestFetcher_Fetch_ResponseMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusMovedPermanently)
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Server", "test-server")
+			_, _ = io.WriteString(w, "<html><title>Final</title></html>")
+		}
+	}))
+	defer server.Close()
+
+	result, err := core.NewFetcher(2*time.Second, "UserAgent").Fetch(context.Background(), server.URL+"/a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", result.StatusCode)
+	}
+	expectedChain := []string{server.URL + "/a", server.URL + "/b"}
+	if len(result.RedirectChain) != 2 || result.RedirectChain[0] != expectedChain[0] || result.RedirectChain[1] != expectedChain[1] {
+		t.Errorf("expected redirect chain %v, got %v", expectedChain, result.RedirectChain)
+	}
+	if result.Timing.TotalMs <= 0 || result.Timing.FirstByteMs <= 0 {
+		t.Errorf("expected total and first-byte timings to be recorded, got %+v", result.Timing)
+	}
+
+	info := result.ResponseInfo([]string{"server", "X-Missing"})
+	if info.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("unexpected content type %q", info.ContentType)
+	}
+	if info.ContentLength != int64(len(result.Body)) {
+		t.Errorf("expected content length %d, got %d", len(result.Body), info.ContentLength)
+	}
+	if len(info.Headers) != 1 || info.Headers["Server"] != "test-server" {
+		t.Errorf("expected only the Server header to be captured, got %v", info.Headers)
+	}
+}
+
+func TestFetcher_Fetch_StatusErrorKeepsMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	result, err := core.NewFetcher(2*time.Second, "UserAgent").Fetch(context.Background(), server.URL)
+	if err == nil {
+		t.Fatal("expected error for 404 response")
+	}
+	if result == nil || result.StatusCode != http.StatusNotFound || result.FinalURL != server.URL {
+		t.Errorf("expected 404 metadata alongside the error, got %+v", result)
+	}
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"strings"
+	"unicode"
+)
+
+// languageStopwords lists frequent function words per ISO 639-1 language code.
+// Words shared by several languages count for each of them.
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "it", "for", "with", "as", "was", "on", "are", "this", "be", "by", "you", "not", "have"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "ein", "eine", "zu", "den", "mit", "sich", "des", "auf", "für", "von", "dem", "auch", "wird", "wir"},
+	"fr": {"le", "la", "les", "et", "des", "est", "une", "du", "dans", "que", "pour", "qui", "pas", "sur", "au", "avec", "sont", "nous", "vous", "ce"},
+	"es": {"el", "la", "los", "las", "y", "que", "es", "en", "una", "del", "por", "con", "para", "se", "no", "su", "al", "como", "más", "pero"},
+	"it": {"il", "la", "che", "di", "è", "per", "una", "sono", "non", "gli", "del", "della", "con", "si", "le", "nel", "anche", "come", "più", "questo"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "zijn", "voor", "met", "ook", "aan", "er", "maar", "om", "wordt", "deze"},
+	"pt": {"o", "a", "os", "as", "que", "não", "uma", "um", "do", "da", "em", "para", "com", "por", "é", "mais", "dos", "das", "se", "como"},
+	"sv": {"och", "att", "det", "som", "en", "på", "är", "av", "för", "med", "till", "den", "har", "inte", "om", "ett", "de", "jag", "var", "men"},
+}
+
+// minLanguageHits is the number of stopwords needed before a language is reported.
+const minLanguageHits = 5
+
+// stopwordLanguages maps each stopword to the languages it belongs to.
+var stopwordLanguages = func() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range languageStopwords {
+		for _, word := range words {
+			index[word] = append(index[word], lang)
+		}
+	}
+	return index
+}()
+
+// detectLanguage guesses the language of the text by counting stopwords. It returns
+// an empty string if too few stopwords were found or two languages are tied.
+func detectLanguage(blocks []string) string {
+	hits := make(map[string]int)
+	for _, block := range blocks {
+		for _, token := range strings.Fields(strings.ToLower(block)) {
+			word := strings.TrimFunc(token, func(r rune) bool { return !unicode.IsLetter(r) })
+			for _, lang := range stopwordLanguages[word] {
+				hits[lang]++
+			}
+		}
+	}
+
+	best, bestHits, tied := "", 0, false
+	for lang, count := range hits {
+		switch {
+		case count > bestHits:
+			best, bestHits, tied = lang, count, false
+		case count == bestHits:
+			tied = true
+		}
+	}
+	if bestHits < minLanguageHits || tied {
+		return ""
+	}
+	return best
+}
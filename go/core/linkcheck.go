@@ -0,0 +1,172 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-scraper/models"
+	"go-scraper/ui"
+)
+
+// LinkCollector is a ResultSink that gathers the unique link and image URLs of
+// successfully scraped pages, together with the pages that reference them.
+// URLs are deduplicated in their normalized form (see normalizeCrawlURL), so
+// fragments and non-HTTP links are ignored.
+type LinkCollector struct {
+	targets []*models.LinkTarget
+	index   map[string]int // by normalized URL
+}
+
+// NewLinkCollector creates an empty collector.
+func NewLinkCollector() *LinkCollector {
+	return &LinkCollector{index: make(map[string]int)}
+}
+
+// Write records the links and images of a page.
+func (c *LinkCollector) Write(page *models.Page) error {
+	if !page.Success() {
+		return nil
+	}
+	c.add(page.URL, models.LinkTypeLink, page.Links)
+	c.add(page.URL, models.LinkTypeImage, page.Images)
+	return nil
+}
+
+// add records the targets of one type referenced by source.
+func (c *LinkCollector) add(source string, linkType models.LinkType, urls []string) {
+	for _, raw := range urls {
+		u, ok := normalizeCrawlURL(raw)
+		if !ok {
+			continue
+		}
+		i, seen := c.index[u]
+		if !seen {
+			i = len(c.targets)
+			c.index[u] = i
+			c.targets = append(c.targets, &models.LinkTarget{URL: u, Type: linkType})
+		}
+		target := c.targets[i]
+		if n := len(target.Sources); n == 0 || target.Sources[n-1] != source {
+			target.Sources = append(target.Sources, source)
+		}
+	}
+}
+
+// Targets returns the collected targets in the order they were first found.
+func (c *LinkCollector) Targets() []models.LinkTarget {
+	targets := make([]models.LinkTarget, len(c.targets))
+	for i, target := range c.targets {
+		targets[i] = *target
+	}
+	return targets
+}
+
+// CheckLinks requests every target with HEAD through fetcher and reports whether it
+// is reachable. Targets answering HEAD with a 4xx or 5xx status are requested again
+// with GET, since some servers do not support HEAD. Up to concurrency targets are
+// checked at the same time; per-host limits, retries and robots.txt rules are applied
+// by the fetcher stack.
+//
+// Context cancellation is respected - once ctx is cancelled (or a graceful stop is
+// requested, see WithGracefulStop) no further targets are checked and the remaining
+// ones get ErrorKindCancelled. WithURLTimeout bounds the time spent per target.
+// Returns one LinkCheck per target, in the order of targets.
+func CheckLinks(ctx context.Context, fetcher HTTPFetcher, targets []models.LinkTarget, concurrency int, opts ...RunOption) []models.LinkCheck {
+	options := newRunOptions(opts)
+
+	// Enforce minimal concurrency of 1
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	pbm := ui.NewProgressBarManager(1)
+	defer pbm.StopRenderer()
+	tracker := pbm.NewTracker(fmt.Sprintf("Checking %d links and images", len(targets)), int64(len(targets)))
+
+	// Each worker writes only to the slots of the indices it received
+	results := make([]models.LinkCheck, len(targets))
+	jobs := make(chan int, len(targets))
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if options.stopped(ctx) {
+					results[i] = models.LinkCheck{
+						LinkTarget: targets[i],
+						ErrorKind:  models.ErrorKindCancelled,
+						Error:      "skipped: run cancelled before the link was checked",
+					}
+				} else {
+					results[i] = checkLink(ctx, fetcher, targets[i], options.timeout)
+				}
+				tracker.Increment(1)
+			}
+		}()
+	}
+
+	for i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// checkLink requests a single target with HEAD, falling back to GET when the server
+// answers HEAD with an error status. Targets interrupted by the cancellation of ctx
+// get ErrorKindCancelled, those exceeding timeout ErrorKindDeadline. Targets on a host
+// whose robots.txt fails with a server error get ErrorKindHTTPStatus, so only a
+// matching robots.txt rule leads to ErrorKindRobotsDisallowed.
+func checkLink(ctx context.Context, fetcher HTTPFetcher, target models.LinkTarget, timeout time.Duration) models.LinkCheck {
+	linkCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		linkCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	check := models.LinkCheck{LinkTarget: target, Method: http.MethodHead}
+	result, err := fetcher.Fetch(WithRequestOptions(linkCtx, RequestOptions{Method: http.MethodHead}), target.URL)
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode >= http.StatusBadRequest {
+		check.Method = http.MethodGet
+		result, err = fetcher.Fetch(WithRequestOptions(linkCtx, RequestOptions{Method: http.MethodGet}), target.URL)
+	}
+
+	if result != nil {
+		check.StatusCode = result.StatusCode
+	}
+	if errors.As(err, &statusErr) {
+		check.StatusCode = statusErr.StatusCode
+		if statusErr.StatusCode < http.StatusBadRequest {
+			return check // e.g. 204 No Content: reachable
+		}
+	}
+	if err == nil {
+		return check
+	}
+
+	check.Error = err.Error()
+	switch {
+	case ctx.Err() != nil:
+		check.ErrorKind = models.ErrorKindCancelled
+	case linkCtx.Err() != nil:
+		check.ErrorKind = models.ErrorKindDeadline
+		check.Error = fmt.Sprintf("deadline exceeded: not completed within %v", timeout)
+	case errors.Is(err, ErrRobotsUnavailable):
+		// The host fails with server errors: broken, not excluded by a robots.txt rule
+		check.ErrorKind = models.ErrorKindHTTPStatus
+	default:
+		check.ErrorKind = ClassifyError(err)
+	}
+	return check
+}
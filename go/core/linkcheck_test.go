@@ -0,0 +1,177 @@
+package core_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-scraper/core"
+	"go-scraper/models"
+)
+
+func TestLinkCollector_DeduplicatesTargets(t *testing.T) {
+	collector := core.NewLinkCollector()
+	_ = collector.Write(&models.Page{
+		URL:    "https://a.com",
+		Links:  []string{"https://b.com/x#top", "https://B.com/x", "mailto:me@a.com"},
+		Images: []string{"https://a.com/logo.png"},
+	})
+	_ = collector.Write(&models.Page{URL: "https://c.com", Links: []string{"https://b.com/x", "https://a.com/logo.png"}})
+	_ = collector.Write(&models.Page{URL: "https://failed.com", Links: []string{"https://d.com"}, Error: "boom"})
+
+	targets := collector.Targets()
+	if len(targets) != 2 {
+		t.Fatalf("expected two unique targets, got %+v", targets)
+	}
+	if targets[0].URL != "https://b.com/x" || targets[0].Type != models.LinkTypeLink ||
+		len(targets[0].Sources) != 2 || targets[0].Sources[1] != "https://c.com" {
+		t.Errorf("unexpected link target: %+v", targets[0])
+	}
+	if targets[1].URL != "https://a.com/logo.png" || targets[1].Type != models.LinkTypeImage || len(targets[1].Sources) != 2 {
+		t.Errorf("unexpected image target: %+v", targets[1])
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string][]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path] = append(requests[r.URL.Path], r.Method)
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/ok":
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	paths := []string{"/ok", "/no-head", "/empty", "/gone", "/error"}
+	targets := make([]models.LinkTarget, len(paths))
+	for i, path := range paths {
+		targets[i] = models.LinkTarget{URL: server.URL + path, Sources: []string{"https://source.com"}}
+	}
+
+	checks := core.CheckLinks(context.Background(), core.NewFetcher(5*time.Second, "test"), targets, 3)
+
+	expected := []struct {
+		method string
+		status int
+		broken bool
+	}{
+		{http.MethodHead, 200, false},
+		{http.MethodGet, 200, false},
+		{http.MethodHead, 204, false},
+		{http.MethodGet, 404, true},
+		{http.MethodGet, 500, true},
+	}
+	for i, want := range expected {
+		check := checks[i]
+		if check.URL != targets[i].URL || len(check.Sources) != 1 {
+			t.Errorf("%s: expected the target to be kept, got %+v", paths[i], check.LinkTarget)
+		}
+		if check.Method != want.method || check.StatusCode != want.status || check.Broken() != want.broken {
+			t.Errorf("%s: expected %s %d (broken %v), got %s %d (broken %v, %s)", paths[i],
+				want.method, want.status, want.broken, check.Method, check.StatusCode, check.Broken(), check.Error)
+		}
+	}
+	if checks[3].ErrorKind != models.ErrorKindHTTPStatus {
+		t.Errorf("expected an http_status failure, got %q", checks[3].ErrorKind)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if methods := requests["/ok"]; len(methods) != 1 || methods[0] != http.MethodHead {
+		t.Errorf("expected a single HEAD request for /ok, got %v", methods)
+	}
+	if methods := requests["/no-head"]; len(methods) != 2 || methods[1] != http.MethodGet {
+		t.Errorf("expected HEAD with GET fallback for /no-head, got %v", methods)
+	}
+}
+
+func TestCheckLinks_TimeoutsAndCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	targets := []models.LinkTarget{{URL: server.URL + "/slow"}}
+	checks := core.CheckLinks(context.Background(), core.NewFetcher(5*time.Second, "test"), targets, 1,
+		core.WithURLTimeout(50*time.Millisecond))
+	if checks[0].ErrorKind != models.ErrorKindDeadline || !checks[0].Broken() {
+		t.Errorf("expected a broken target with a deadline failure, got %+v", checks[0])
+	}
+
+	stop, cancel := context.WithCancel(context.Background())
+	cancel()
+	checks = core.CheckLinks(context.Background(), core.NewFetcher(5*time.Second, "test"), targets, 1, core.WithGracefulStop(stop))
+	if checks[0].ErrorKind != models.ErrorKindCancelled || !checks[0].Skipped() {
+		t.Errorf("expected a skipped target after a graceful stop, got %+v", checks[0])
+	}
+}
+
+func TestCheckLinks_RobotsSkipsOnlyRuleMatches(t *testing.T) {
+	ruled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = io.WriteString(w, "User-agent: *\nDisallow: /private\n")
+		}
+	}))
+	defer ruled.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	// An address nobody listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + listener.Addr().String()
+	_ = listener.Close()
+
+	targets := []models.LinkTarget{
+		{URL: ruled.URL + "/private"},
+		{URL: ruled.URL + "/public"},
+		{URL: failing.URL + "/page"},
+		{URL: closed + "/page"},
+	}
+	fetcher := core.NewRobotsFetcher(core.NewFetcher(5*time.Second, "test"), "test")
+	checks := core.CheckLinks(context.Background(), fetcher, targets, 2)
+
+	expected := []struct {
+		kind    models.ErrorKind
+		skipped bool
+		broken  bool
+	}{
+		{models.ErrorKindRobotsDisallowed, true, false},
+		{"", false, false},
+		{models.ErrorKindHTTPStatus, false, true},
+		{models.ErrorKindConnectionRefused, false, true},
+	}
+	for i, want := range expected {
+		check := checks[i]
+		if check.ErrorKind != want.kind || check.Skipped() != want.skipped || check.Broken() != want.broken {
+			t.Errorf("%s: expected kind %q (skipped %v, broken %v), got %q (skipped %v, broken %v, %s)", check.URL,
+				want.kind, want.skipped, want.broken, check.ErrorKind, check.Skipped(), check.Broken(), check.Error)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"go-scraper/models"
+)
+
+// metadataCollector gathers page metadata from the elements visited while the
+// document is traversed. URLs are resolved once the base URL is known.
+type metadataCollector struct {
+	meta         models.Metadata
+	rawCanonical string
+	rawAlternate []models.AlternateLink
+}
+
+// visit records the metadata carried by an element, if any.
+func (c *metadataCollector) visit(n *html.Node) {
+	switch n.Data {
+	case "html":
+		setFirst(&c.meta.Lang, attrValue(n, "lang"))
+	case "meta":
+		c.visitMeta(n)
+	case "link":
+		rel := strings.Fields(strings.ToLower(attrValue(n, "rel")))
+		href := attrValue(n, "href")
+		switch {
+		case slices.Contains(rel, "canonical"):
+			setFirst(&c.rawCanonical, href)
+		case slices.Contains(rel, "alternate"):
+			if lang := strings.TrimSpace(attrValue(n, "hreflang")); lang != "" && strings.TrimSpace(href) != "" {
+				c.rawAlternate = append(c.rawAlternate, models.AlternateLink{Hreflang: lang, URL: href})
+			}
+		}
+	case "script":
+		if !strings.EqualFold(strings.TrimSpace(attrValue(n, "type")), "application/ld+json") {
+			return
+		}
+		var sb strings.Builder
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.TextNode {
+				sb.WriteString(child.Data)
+			}
+		}
+		// Invalid blocks are skipped; they are a common authoring error
+		var data any
+		if err := json.Unmarshal([]byte(sb.String()), &data); err == nil {
+			c.meta.JSONLD = append(c.meta.JSONLD, data)
+		}
+	}
+}
+
+// visitMeta records description, robots, Open Graph and Twitter card <meta> tags.
+// Open Graph uses the property attribute; Twitter cards are found in both.
+func (c *metadataCollector) visitMeta(n *html.Node) {
+	content := strings.TrimSpace(attrValue(n, "content"))
+	if content == "" {
+		return
+	}
+
+	name := strings.ToLower(strings.TrimSpace(attrValue(n, "name")))
+	property := strings.ToLower(strings.TrimSpace(attrValue(n, "property")))
+	switch {
+	case name == "description":
+		setFirst(&c.meta.Description, content)
+	case name == "robots":
+		setFirst(&c.meta.Robots, content)
+	case strings.HasPrefix(property, "og:"):
+		c.meta.OpenGraph = setFirstKey(c.meta.OpenGraph, property, content)
+	case strings.HasPrefix(name, "twitter:"):
+		c.meta.Twitter = setFirstKey(c.meta.Twitter, name, content)
+	case strings.HasPrefix(property, "twitter:"):
+		c.meta.Twitter = setFirstKey(c.meta.Twitter, property, content)
+	}
+}
+
+// result resolves the collected URLs and returns the metadata, or nil if none was found.
+func (c *metadataCollector) result(resolver *urlResolver) *models.Metadata {
+	meta := c.meta
+	if resolved, ok := resolver.resolve(c.rawCanonical); ok {
+		meta.Canonical = resolved.url
+	}
+	for _, alternate := range c.rawAlternate {
+		if resolved, ok := resolver.resolve(alternate.URL); ok && resolved.http {
+			meta.Hreflang = append(meta.Hreflang, models.AlternateLink{Hreflang: alternate.Hreflang, URL: resolved.url})
+		}
+	}
+	if meta.IsEmpty() {
+		return nil
+	}
+	return &meta
+}
+
+// setFirst stores the trimmed value unless the target is already set.
+func setFirst(target *string, value string) {
+	if *target == "" {
+		*target = strings.TrimSpace(value)
+	}
+}
+
+// setFirstKey stores value under key unless the key is already present,
+// creating the map on first use.
+func setFirstKey(m map[string]string, key, value string) map[string]string {
+	if m == nil {
+		m = make(map[string]string)
+	}
+	if _, ok := m[key]; !ok {
+		m[key] = value
+	}
+	return m
+}
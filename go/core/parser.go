@@ -2,11 +2,31 @@ package core
 
 import (
 	"io"
+	"net/url"
 	"strings"
 
 	"golang.org/x/net/html"
+
+	"go-scraper/models"
 )
 
+// ParseResult holds the structured data extracted from an HTML document.
+type ParseResult struct {
+	Title      string           // Text content of the first <title> element (trimmed)
+	Links      []string         // Deduplicated http(s) links from <a> elements, resolved and without fragments
+	Images     []string         // Deduplicated http(s) image sources from <img> elements, resolved and without fragments
+	OtherLinks []string         // Deduplicated links with non-HTTP schemes such as mailto:, tel: or javascript:
+	Fields     map[string]any   // Custom fields extracted by ParseOptions.Fields (nil if none matched)
+	Metadata   *models.Metadata // Description, canonical URL, Open Graph, JSON-LD and other metadata (nil if none)
+	Content    *models.Content  // Main-content statistics and heading outline (nil without <body>)
+}
+
+// ParseOptions enables optional extraction steps of ParsePageWithOptions.
+type ParseOptions struct {
+	Fields    *FieldExtractor // Custom field rules evaluated against the document (nil = none)
+	StoreText bool            // Keep the main-content text in ParseResult.Content
+}
+
 // ParseHTML extracts structured data from an HTML document.
 // It parses the document to find:
 //   - The text content of the first <title> element (trimmed of whitespace)
@@ -14,37 +34,68 @@ import (
 //   - All src attributes from <img> elements (image sources)
 //
 // Returns the page title, slice of links, slice of images, and any parsing error.
-// Empty strings in href or src attributes are excluded from results.
+// Empty strings in href or src attributes are excluded from results. As no page URL
+// is known, relative references are returned unresolved; use ParsePage to resolve them.
 func ParseHTML(body io.Reader) (string, []string, []string, error) {
-	doc, err := html.Parse(body)
+	result, err := ParsePage(body, "")
 	if err != nil {
 		return "", nil, nil, err
 	}
+	return result.Title, result.Links, result.Images, nil
+}
+
+// ParsePage extracts structured data from an HTML document fetched from pageURL.
+// Links and images are normalized to absolute URLs using pageURL (which should be
+// the final URL after redirects) and the document's <base href>, if present.
+// Fragments are stripped and duplicates removed while preserving document order.
+// Links with non-HTTP schemes (mailto:, tel:, javascript:, ...) are reported
+// separately in OtherLinks; image sources with such schemes (e.g. data:) are dropped.
+// Page metadata (<html lang>, description and robots meta tags, canonical and hreflang
+// links, Open Graph and Twitter card properties, JSON-LD blocks) is collected in the
+// same traversal. The main content is analyzed for word count, reading time, heading
+// outline and language (see ParseResult.Content).
+func ParsePage(body io.Reader, pageURL string) (*ParseResult, error) {
+	return ParsePageWithOptions(body, pageURL, ParseOptions{})
+}
+
+// ParsePageWithOptions works like ParsePage and additionally runs the extraction
+// steps enabled in opts against the same parsed document.
+func ParsePageWithOptions(body io.Reader, pageURL string, opts ParseOptions) (*ParseResult, error) {
+	doc, err := html.Parse(body)
+	if err != nil {
+		return nil, err
+	}
 
 	var title string
-	var links []string
-	var images []string
+	var baseHref string
+	var rawLinks []string
+	var rawImages []string
+	var metadata metadataCollector
+	var roots contentRoots
 
 	var traverse func(*html.Node)
 	traverse = func(n *html.Node) {
 		if n.Type == html.ElementNode {
+			roots.visit(n)
 			switch n.Data {
 			case "title":
-				if n.FirstChild != nil {
+				if title == "" && n.FirstChild != nil {
 					title = n.FirstChild.Data
 				}
+			case "base":
+				if baseHref == "" {
+					baseHref = attrValue(n, "href")
+				}
 			case "a":
-				for _, attr := range n.Attr {
-					if attr.Key == "href" && attr.Val != "" {
-						links = append(links, attr.Val)
-					}
+				if href := attrValue(n, "href"); href != "" {
+					rawLinks = append(rawLinks, href)
 				}
 			case "img":
-				for _, attr := range n.Attr {
-					if attr.Key == "src" && attr.Val != "" {
-						images = append(images, attr.Val)
-					}
+				if src := attrValue(n, "src"); src != "" {
+					rawImages = append(rawImages, src)
 				}
+			case "html", "meta", "link", "script":
+				metadata.visit(n)
 			}
 		}
 		for c := n.FirstChild; c != nil; c = c.NextSibling {
@@ -54,5 +105,119 @@ func ParseHTML(body io.Reader) (string, []string, []string, error) {
 
 	traverse(doc)
 
-	return strings.TrimSpace(title), links, images, nil
+	resolver := newURLResolver(pageURL, baseHref)
+	result := &ParseResult{
+		Title:    strings.TrimSpace(title),
+		Fields:   opts.Fields.extract(doc, resolver),
+		Metadata: metadata.result(resolver),
+		Content:  analyzeContent(roots, opts.StoreText),
+	}
+
+	seenLinks := make(map[string]struct{})
+	seenOther := make(map[string]struct{})
+	for _, raw := range rawLinks {
+		resolved, ok := resolver.resolve(raw)
+		if !ok {
+			continue
+		}
+		if resolved.http {
+			result.Links = appendUnique(result.Links, seenLinks, resolved.url)
+		} else {
+			result.OtherLinks = appendUnique(result.OtherLinks, seenOther, resolved.url)
+		}
+	}
+
+	seenImages := make(map[string]struct{})
+	for _, raw := range rawImages {
+		if resolved, ok := resolver.resolve(raw); ok && resolved.http {
+			result.Images = appendUnique(result.Images, seenImages, resolved.url)
+		}
+	}
+
+	return result, nil
+}
+
+// attrValue returns the value of the named attribute, or an empty string if it is missing.
+func attrValue(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// appendUnique appends value to list unless it was already recorded in seen.
+func appendUnique(list []string, seen map[string]struct{}, value string) []string {
+	if _, ok := seen[value]; ok {
+		return list
+	}
+	seen[value] = struct{}{}
+	return append(list, value)
+}
+
+// urlResolver resolves references found in a document against its base URL.
+type urlResolver struct {
+	base *url.URL // nil when the document URL is unknown
+}
+
+// resolvedURL is a normalized reference and whether it uses an http(s) scheme.
+type resolvedURL struct {
+	url  string
+	http bool
+}
+
+// newURLResolver determines the base URL from the page URL and an optional <base href>.
+// A relative <base href> is itself resolved against the page URL.
+func newURLResolver(pageURL, baseHref string) *urlResolver {
+	var base *url.URL
+	if pageURL != "" {
+		if u, err := url.Parse(pageURL); err == nil && u.IsAbs() {
+			base = u
+		}
+	}
+
+	if href := strings.TrimSpace(baseHref); href != "" {
+		if ref, err := url.Parse(href); err == nil {
+			if base != nil {
+				base = base.ResolveReference(ref)
+			} else if ref.IsAbs() {
+				base = ref
+			}
+		}
+	}
+
+	return &urlResolver{base: base}
+}
+
+// resolve normalizes a raw href/src value. Returns false for empty or unparsable values.
+// Without a base URL relative references are kept as written (minus the fragment).
+func (r *urlResolver) resolve(raw string) (resolvedURL, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return resolvedURL{}, false
+	}
+
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return resolvedURL{}, false
+	}
+
+	scheme := strings.ToLower(ref.Scheme)
+	if scheme != "" && scheme != "http" && scheme != "https" {
+		return resolvedURL{url: raw, http: false}, true
+	}
+
+	if r.base != nil {
+		ref = r.base.ResolveReference(ref)
+	}
+	ref.Fragment = ""
+	ref.RawFragment = ""
+
+	resolved := ref.String()
+	if resolved == "" {
+		// Fragment-only reference without a base URL
+		return resolvedURL{}, false
+	}
+	return resolvedURL{url: resolved, http: true}, true
 }
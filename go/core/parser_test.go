@@ -1,10 +1,12 @@
 package core_test
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
 	"go-scraper/core"
+	"go-scraper/models"
 )
 
 func TestParseHTML(t *testing.T) {
@@ -47,3 +49,122 @@ func TestParseHTML(t *testing.T) {
 		}
 	}
 }
+
+func TestParsePage_ResolvesAndNormalizes(t *testing.T) {
+	htmlData := `
+	<html>
+	  <head><title>Docs</title></head>
+	  <body>
+	    <a href="/local/link#section">Local</a>
+	    <a href="/local/link">Local again</a>
+	    <a href="sub/page">Relative</a>
+	    <a href="https://other.com/x#top">External</a>
+	    <a href="mailto:team@example.com">Mail</a>
+	    <a href="javascript:void(0)">JS</a>
+	    <a href="tel:+4912345">Phone</a>
+	    <img src="../img.png">
+	    <img src="data:image/png;base64,AAAA">
+	  </body>
+	</html>`
+
+	result, err := core.ParsePage(strings.NewReader(htmlData), "https://example.com/docs/index.html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedLinks := []string{
+		"https://example.com/local/link",
+		"https://example.com/docs/sub/page",
+		"https://other.com/x",
+	}
+	assertStrings(t, "links", result.Links, expectedLinks)
+	assertStrings(t, "images", result.Images, []string{"https://example.com/img.png"})
+	assertStrings(t, "other links", result.OtherLinks, []string{
+		"mailto:team@example.com",
+		"javascript:void(0)",
+		"tel:+4912345",
+	})
+}
+
+func TestParsePage_UsesBaseHref(t *testing.T) {
+	htmlData := `
+	<html>
+	  <head><base href="/static/"></head>
+	  <body><a href="page.html">Page</a><img src="img/a.png"></body>
+	</html>`
+
+	result, err := core.ParsePage(strings.NewReader(htmlData), "https://example.com/docs/index.html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertStrings(t, "links", result.Links, []string{"https://example.com/static/page.html"})
+	assertStrings(t, "images", result.Images, []string{"https://example.com/static/img/a.png"})
+}
+
+func TestParsePage_ExtractsMetadata(t *testing.T) {
+	htmlData := `
+	<html lang="en-GB">
+	  <head>
+	    <title>Docs</title>
+	    <meta name="Description" content=" All the docs ">
+	    <meta name="description" content="Ignored duplicate">
+	    <meta name="robots" content="noindex, follow">
+	    <link rel="canonical" href="/docs/#top">
+	    <link rel="alternate" hreflang="de" href="/de/docs/">
+	    <link rel="alternate" hreflang="x-default" href="https://example.com/docs/">
+	    <link rel="alternate" type="application/rss+xml" href="/feed.xml">
+	    <meta property="og:title" content="Docs">
+	    <meta property="og:image" content="https://example.com/og.png">
+	    <meta name="twitter:card" content="summary">
+	    <meta property="twitter:site" content="@example">
+	    <script type="application/ld+json">{"@type": "WebPage", "name": "Docs"}</script>
+	    <script type="application/ld+json">{not json</script>
+	  </head>
+	  <body></body>
+	</html>`
+
+	result, err := core.ParsePage(strings.NewReader(htmlData), "https://example.com/docs/index.html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &models.Metadata{
+		Lang:        "en-GB",
+		Description: "All the docs",
+		Robots:      "noindex, follow",
+		Canonical:   "https://example.com/docs/",
+		Hreflang: []models.AlternateLink{
+			{Hreflang: "de", URL: "https://example.com/de/docs/"},
+			{Hreflang: "x-default", URL: "https://example.com/docs/"},
+		},
+		OpenGraph: map[string]string{"og:title": "Docs", "og:image": "https://example.com/og.png"},
+		Twitter:   map[string]string{"twitter:card": "summary", "twitter:site": "@example"},
+		JSONLD:    []any{map[string]any{"@type": "WebPage", "name": "Docs"}},
+	}
+	if !reflect.DeepEqual(result.Metadata, expected) {
+		t.Errorf("expected metadata %+v, got %+v", expected, result.Metadata)
+	}
+}
+
+func TestParsePage_NoMetadata(t *testing.T) {
+	result, err := core.ParsePage(strings.NewReader(`<html><head><title>T</title></head></html>`), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata != nil {
+		t.Errorf("expected no metadata, got %+v", result.Metadata)
+	}
+}
+
+func assertStrings(t *testing.T, name string, got, expected []string) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d %s, got %d: %#v", len(expected), name, len(got), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %s %d to be '%s', got '%s'", name, i, expected[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ProxyRotation selects how a ProxyPool distributes requests over its proxies.
+type ProxyRotation string
+
+const (
+	// ProxyRoundRobin sends each request through the next proxy of the list.
+	ProxyRoundRobin ProxyRotation = "round-robin"
+	// ProxyPerHost sends all requests to a host through the same proxy, so sessions
+	// and per-host rate limits stay with one exit address.
+	ProxyPerHost ProxyRotation = "per-host"
+)
+
+// proxySchemes are the proxy URL schemes supported by net/http.
+var proxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// ProxyPool routes requests through a list of HTTP, HTTPS or SOCKS5 proxies, with
+// credentials given as user:password in the proxy URL. Proxies that cannot be
+// reached or reject the credentials are benched: they are skipped until the bench
+// duration has passed. If all proxies are benched, the one whose bench ends first
+// is used. Hosts matching the no-proxy list are requested directly.
+type ProxyPool struct {
+	proxies  []*url.URL
+	rotation ProxyRotation
+	bench    time.Duration
+	noProxy  []string
+	now      func() time.Time
+
+	mu           sync.Mutex
+	next         int         // round-robin position
+	benchedUntil []time.Time // per proxy
+}
+
+// NewProxyPool creates a pool of the given proxy URLs. An empty rotation means
+// round-robin; a bench duration of zero disables benching. noProxy is a
+// comma-separated list in NO_PROXY syntax: host names and domains (matching their
+// subdomains, with or without a leading dot), IP addresses, CIDR ranges, or "*".
+func NewProxyPool(proxyURLs []string, rotation ProxyRotation, bench time.Duration, noProxy string) (*ProxyPool, error) {
+	if len(proxyURLs) == 0 {
+		return nil, errors.New("at least one proxy URL is required")
+	}
+	if rotation == "" {
+		rotation = ProxyRoundRobin
+	}
+	if rotation != ProxyRoundRobin && rotation != ProxyPerHost {
+		return nil, fmt.Errorf("unknown rotation %q (expected %s or %s)", rotation, ProxyRoundRobin, ProxyPerHost)
+	}
+
+	pool := &ProxyPool{
+		rotation:     rotation,
+		bench:        bench,
+		now:          time.Now,
+		benchedUntil: make([]time.Time, len(proxyURLs)),
+	}
+	for i, raw := range proxyURLs {
+		// Parse errors are not wrapped, since they would repeat the credentials
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("proxy %d: invalid URL", i+1)
+		}
+		if !slices.Contains(proxySchemes, strings.ToLower(u.Scheme)) || u.Host == "" {
+			return nil, fmt.Errorf("proxy %s: expected a URL with scheme %s and a host", u.Redacted(), strings.Join(proxySchemes, ", "))
+		}
+		pool.proxies = append(pool.proxies, u)
+	}
+	for _, entry := range strings.Split(noProxy, ",") {
+		if entry = strings.Trim(strings.ToLower(strings.TrimSpace(entry)), "."); entry != "" {
+			pool.noProxy = append(pool.noProxy, entry)
+		}
+	}
+	return pool, nil
+}
+
+// Proxy returns the proxy for a request, or nil if its host bypasses the proxies.
+// It has the signature of http.Transport.Proxy.
+func (p *ProxyPool) Proxy(req *http.Request) (*url.URL, error) {
+	host := strings.ToLower(req.URL.Hostname())
+	if p.bypasses(host) {
+		return nil, nil
+	}
+	proxy := p.pick(host)
+	if used, ok := req.Context().Value(proxyUsedKey{}).(*atomic.Pointer[url.URL]); ok {
+		used.Store(proxy)
+	}
+	return proxy, nil
+}
+
+// pick selects the proxy for a host, skipping benched proxies.
+func (p *ProxyPool) pick(host string) *url.URL {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.proxies)
+	start := p.next
+	if p.rotation == ProxyPerHost {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(host))
+		start = int(h.Sum32() % uint32(n))
+	}
+
+	now := p.now()
+	soonest := start
+	for i := range n {
+		idx := (start + i) % n
+		if !now.Before(p.benchedUntil[idx]) {
+			if p.rotation == ProxyRoundRobin {
+				p.next = (idx + 1) % n
+			}
+			return p.proxies[idx]
+		}
+		if p.benchedUntil[idx].Before(p.benchedUntil[soonest]) {
+			soonest = idx
+		}
+	}
+	// All proxies are benched
+	return p.proxies[soonest]
+}
+
+// Bench takes a proxy out of rotation for the bench duration.
+func (p *ProxyPool) Bench(proxy *url.URL) {
+	if p.bench <= 0 || proxy == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, candidate := range p.proxies {
+		if candidate.String() == proxy.String() {
+			p.benchedUntil[i] = p.now().Add(p.bench)
+		}
+	}
+}
+
+// bypasses reports whether host matches the no-proxy list.
+func (p *ProxyPool) bypasses(host string) bool {
+	ip := net.ParseIP(host)
+	for _, entry := range p.noProxy {
+		switch {
+		case entry == "*" || entry == host:
+			return true
+		case ip != nil:
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(ip) {
+				return true
+			}
+		case strings.HasSuffix(host, "."+entry):
+			return true
+		}
+	}
+	return false
+}
+
+// proxyUsedKey is the context key under which a request records the proxy it used.
+type proxyUsedKey struct{}
+
+// Transport returns an HTTP transport (a clone of http.DefaultTransport) that sends
+// requests through the pool and benches proxies that fail: connection errors to the
+// proxy and 407 Proxy Authentication Required answers.
+func (p *ProxyPool) Transport() http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = p.Proxy
+	transport.OnProxyConnectResponse = func(_ context.Context, proxy *url.URL, _ *http.Request, resp *http.Response) error {
+		if resp.StatusCode == http.StatusProxyAuthRequired {
+			p.Bench(proxy)
+		}
+		return nil // the transport reports the failed CONNECT itself
+	}
+	return &proxyRoundTripper{pool: p, base: transport}
+}
+
+// proxyRoundTripper benches the proxy of a request that failed because of the proxy.
+type proxyRoundTripper struct {
+	pool *ProxyPool
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request, recording which proxy it went through.
+func (rt *proxyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var used atomic.Pointer[url.URL]
+	resp, err := rt.base.RoundTrip(req.WithContext(context.WithValue(req.Context(), proxyUsedKey{}, &used)))
+
+	var opErr *net.OpError
+	switch {
+	case err != nil && errors.As(err, &opErr) && opErr.Op == "proxyconnect":
+		rt.pool.Bench(used.Load())
+	case err == nil && resp.StatusCode == http.StatusProxyAuthRequired:
+		rt.pool.Bench(used.Load())
+	}
+	if resp != nil {
+		resp.Request = req
+	}
+	return resp, err
+}
@@ -0,0 +1,218 @@
+package core_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go-scraper/core"
+)
+
+// proxyFor returns the proxy the pool chooses for rawURL.
+func proxyFor(t *testing.T, pool *core.ProxyPool, rawURL string) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := pool.Proxy(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proxy == nil {
+		return ""
+	}
+	return proxy.Host
+}
+
+func TestNewProxyPool_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		urls     []string
+		rotation core.ProxyRotation
+		wantErr  string
+	}{
+		{"valid", []string{"http://p1:8080", "https://p2", "socks5://user:pass@p3:1080", "socks5h://p4:1080"}, core.ProxyPerHost, ""},
+		{"default rotation", []string{"http://p1:8080"}, "", ""},
+		{"no proxies", nil, "", "at least one proxy"},
+		{"unsupported scheme", []string{"ftp://user:secret@p1"}, "", "expected a URL with scheme"},
+		{"missing host", []string{"p1:8080"}, "", "expected a URL with scheme"},
+		{"unknown rotation", []string{"http://p1:8080"}, "random", "unknown rotation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := core.NewProxyPool(tt.urls, tt.rotation, time.Minute, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if strings.Contains(err.Error(), "secret") {
+				t.Errorf("error leaks the proxy password: %v", err)
+			}
+		})
+	}
+}
+
+func TestProxyPool_Rotation(t *testing.T) {
+	proxies := []string{"http://p1:8080", "http://p2:8080", "http://p3:8080"}
+
+	roundRobin, err := core.NewProxyPool(proxies, core.ProxyRoundRobin, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for range 4 {
+		got = append(got, proxyFor(t, roundRobin, "https://a.com/page"))
+	}
+	if strings.Join(got, ",") != "p1:8080,p2:8080,p3:8080,p1:8080" {
+		t.Errorf("expected round-robin order, got %v", got)
+	}
+
+	perHost, err := core.NewProxyPool(proxies, core.ProxyPerHost, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := proxyFor(t, perHost, "https://a.com/one")
+	for _, rawURL := range []string{"https://a.com/two", "http://A.com:8080/three"} {
+		if proxy := proxyFor(t, perHost, rawURL); proxy != first {
+			t.Errorf("%s: expected the proxy of the host %s, got %s", rawURL, first, proxy)
+		}
+	}
+}
+
+func TestProxyPool_Bench(t *testing.T) {
+	pool, err := core.NewProxyPool([]string{"http://p1:8080", "http://p2:8080"}, core.ProxyPerHost, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	home := proxyFor(t, pool, "https://a.com")
+	other := "p1:8080"
+	if home == other {
+		other = "p2:8080"
+	}
+
+	pool.Bench(&url.URL{Scheme: "http", Host: home})
+	if proxy := proxyFor(t, pool, "https://a.com"); proxy != other {
+		t.Errorf("expected the benched proxy to be skipped, got %s", proxy)
+	}
+
+	// With every proxy benched, the one returning first is used
+	pool.Bench(&url.URL{Scheme: "http", Host: other})
+	if proxy := proxyFor(t, pool, "https://a.com"); proxy != home {
+		t.Errorf("expected the proxy benched first, got %s", proxy)
+	}
+
+	disabled, err := core.NewProxyPool([]string{"http://p1:8080", "http://p2:8080"}, core.ProxyRoundRobin, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabled.Bench(&url.URL{Scheme: "http", Host: "p1:8080"})
+	if proxy := proxyFor(t, disabled, "https://a.com"); proxy != "p1:8080" {
+		t.Errorf("expected no benching with a zero bench duration, got %s", proxy)
+	}
+}
+
+func TestProxyPool_NoProxy(t *testing.T) {
+	pool, err := core.NewProxyPool([]string{"http://p1:8080"}, "", time.Minute, "internal.example, .corp.test, 10.0.0.0/8, 192.168.1.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		url    string
+		direct bool
+	}{
+		{"https://internal.example/status", true},
+		{"https://api.internal.example", true},
+		{"https://wiki.corp.test", true},
+		{"http://10.1.2.3:8080", true},
+		{"http://192.168.1.5", true},
+		{"http://192.168.1.6", false},
+		{"https://notinternal.example", false},
+		{"https://a.com", false},
+	}
+	for _, tt := range tests {
+		if direct := proxyFor(t, pool, tt.url) == ""; direct != tt.direct {
+			t.Errorf("%s: expected direct %v, got %v", tt.url, tt.direct, direct)
+		}
+	}
+}
+
+func TestProxyPool_Transport(t *testing.T) {
+	// A forward proxy answering plain HTTP requests itself
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") == "" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		_, _ = w.Write([]byte("<html><head><title>" + r.URL.Host + "</title></head></html>"))
+	}))
+	defer live.Close()
+
+	// An address nobody listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := listener.Addr().String()
+	_ = listener.Close()
+
+	liveURL := strings.Replace(live.URL, "http://", "http://user:pass@", 1)
+	pool, err := core.NewProxyPool([]string{"http://" + dead, liveURL}, core.ProxyRoundRobin, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetcher := core.NewFetcher(5*time.Second, "test")
+	fetcher.Client.Transport = pool.Transport()
+
+	if _, err := fetcher.Fetch(context.Background(), "http://site.test/"); err == nil {
+		t.Fatal("expected the request through the unreachable proxy to fail")
+	}
+	for range 2 {
+		result, err := fetcher.Fetch(context.Background(), "http://site.test/")
+		if err != nil {
+			t.Fatalf("expected the unreachable proxy to be benched, got %v", err)
+		}
+		if !strings.Contains(string(result.Body), "site.test") {
+			t.Errorf("expected the response of the proxy, got %q", result.Body)
+		}
+	}
+}
+
+func TestProxyPool_TransportBenchesRejectedCredentials(t *testing.T) {
+	var rejecting, accepting int
+	rejectingProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rejecting++
+		w.WriteHeader(http.StatusProxyAuthRequired)
+	}))
+	defer rejectingProxy.Close()
+	acceptingProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepting++
+	}))
+	defer acceptingProxy.Close()
+
+	pool, err := core.NewProxyPool([]string{rejectingProxy.URL, acceptingProxy.URL}, core.ProxyRoundRobin, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: pool.Transport()}
+	for range 4 {
+		resp, err := client.Get("http://site.test/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	if rejecting != 1 || accepting != 3 {
+		t.Errorf("expected the rejecting proxy to be benched after one request, got %d rejected and %d accepted", rejecting, accepting)
+	}
+}
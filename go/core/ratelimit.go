@@ -0,0 +1,163 @@
+package core
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitedFetcher is an HTTPFetcher decorator that applies politeness limits per host.
+// Each host gets its own token bucket (RequestsPerSecond with Burst) and an optional cap
+// on concurrent requests (MaxInFlightPerHost). Requests to different hosts never block
+// each other, so parallel runs still benefit from concurrency across sites.
+type RateLimitedFetcher struct {
+	Fetcher            HTTPFetcher // Underlying fetcher
+	RequestsPerSecond  float64     // Sustained request rate per host (<= 0 = unlimited)
+	Burst              int         // Requests allowed in a burst before rate limiting applies (min 1)
+	MaxInFlightPerHost int         // Maximum concurrent requests per host (<= 0 = unlimited)
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+}
+
+// hostLimiter holds the limiting state for a single host.
+type hostLimiter struct {
+	bucket *tokenBucket  // nil when the request rate is unlimited
+	slots  chan struct{} // nil when in-flight requests are unlimited
+}
+
+// NewRateLimitedFetcher wraps fetcher with per-host rate and concurrency limits.
+func NewRateLimitedFetcher(fetcher HTTPFetcher, requestsPerSecond float64, burst, maxInFlightPerHost int) *RateLimitedFetcher {
+	return &RateLimitedFetcher{
+		Fetcher:            fetcher,
+		RequestsPerSecond:  requestsPerSecond,
+		Burst:              burst,
+		MaxInFlightPerHost: maxInFlightPerHost,
+		hosts:              make(map[string]*hostLimiter),
+	}
+}
+
+// Fetch waits for a free in-flight slot and a rate token for the URL's host,
+// then delegates to the wrapped fetcher. Waiting respects context cancellation.
+func (r *RateLimitedFetcher) Fetch(ctx context.Context, rawURL string) (*FetchResult, error) {
+	limiter := r.limiter(hostKey(rawURL))
+
+	if limiter.slots != nil {
+		select {
+		case limiter.slots <- struct{}{}:
+			defer func() { <-limiter.slots }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if limiter.bucket != nil {
+		if err := limiter.bucket.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.Fetcher.Fetch(ctx, rawURL)
+}
+
+// limiter returns the limiter for a host, creating it on first use.
+func (r *RateLimitedFetcher) limiter(host string) *hostLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hosts == nil {
+		r.hosts = make(map[string]*hostLimiter)
+	}
+	if limiter, ok := r.hosts[host]; ok {
+		return limiter
+	}
+
+	limiter := &hostLimiter{}
+	if r.RequestsPerSecond > 0 {
+		limiter.bucket = newTokenBucket(r.RequestsPerSecond, r.Burst)
+	}
+	if r.MaxInFlightPerHost > 0 {
+		limiter.slots = make(chan struct{}, r.MaxInFlightPerHost)
+	}
+	r.hosts[host] = limiter
+	return limiter
+}
+
+// hostKey returns the lowercase host of a URL, or the raw URL if it cannot be parsed.
+func hostKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return strings.ToLower(u.Host)
+}
+
+// tokenBucket is a simple token bucket rate limiter. Tokens refill continuously
+// at rate per second up to burst; each request consumes one token.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full bucket with the given rate and burst size.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it.
+// The balance may become negative, which queues later callers behind earlier ones.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+}
+
+// wait blocks until a token is available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,98 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-scraper/core"
+)
+
+// countingFetcher records how many fetches run concurrently.
+type countingFetcher struct {
+	delay    time.Duration
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+}
+
+func (c *countingFetcher) Fetch(_ context.Context, url string) (*core.FetchResult, error) {
+	current := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		seen := c.maxSeen.Load()
+		if current <= seen || c.maxSeen.CompareAndSwap(seen, current) {
+			break
+		}
+	}
+	time.Sleep(c.delay)
+	return &core.FetchResult{FinalURL: url}, nil
+}
+
+func fetchConcurrently(t *testing.T, fetcher core.HTTPFetcher, urls []string) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for _, u := range urls {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+			if _, err := fetcher.Fetch(context.Background(), u); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(u)
+	}
+	wg.Wait()
+}
+
+func TestRateLimitedFetcher_RequestsPerSecond(t *testing.T) {
+	fetcher := core.NewRateLimitedFetcher(&countingFetcher{}, 20, 1, 0)
+
+	start := time.Now()
+	fetchConcurrently(t, fetcher, []string{"https://a.com/1", "https://a.com/2", "https://a.com/3"})
+
+	// First request uses the burst token, the other two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected requests to the same host to be rate limited, took %v", elapsed)
+	}
+}
+
+func TestRateLimitedFetcher_DifferentHostsNotLimited(t *testing.T) {
+	fetcher := core.NewRateLimitedFetcher(&countingFetcher{}, 1, 1, 0)
+
+	start := time.Now()
+	fetchConcurrently(t, fetcher, []string{"https://a.com/", "https://b.com/", "https://c.com/"})
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected requests to different hosts to proceed immediately, took %v", elapsed)
+	}
+}
+
+func TestRateLimitedFetcher_MaxInFlightPerHost(t *testing.T) {
+	inner := &countingFetcher{delay: 20 * time.Millisecond}
+	fetcher := core.NewRateLimitedFetcher(inner, 0, 0, 2)
+
+	fetchConcurrently(t, fetcher, []string{
+		"https://a.com/1", "https://a.com/2", "https://a.com/3", "https://a.com/4", "https://a.com/5",
+	})
+
+	if got := inner.maxSeen.Load(); got > 2 {
+		t.Errorf("expected at most 2 concurrent requests per host, got %d", got)
+	}
+}
+
+func TestRateLimitedFetcher_ContextCancelled(t *testing.T) {
+	fetcher := core.NewRateLimitedFetcher(&countingFetcher{}, 0.1, 1, 0)
+	if _, err := fetcher.Fetch(context.Background(), "https://a.com/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := fetcher.Fetch(ctx, "https://a.com/again")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded while waiting for a token, got %v", err)
+	}
+}
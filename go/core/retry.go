@@ -0,0 +1,157 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// DefaultRetryableStatuses lists the HTTP status codes that are retried by default.
+var DefaultRetryableStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryFetcher is an HTTPFetcher decorator that retries transient failures.
+// Timeouts, connection resets and responses with a retryable status code are retried
+// with exponential backoff and jitter, up to MaxAttempts in total. A Retry-After header
+// on the failed response takes precedence over the computed backoff when it is longer;
+// if it asks for more than MaxBackoff, the failure is returned instead of blocking the
+// worker that long. Waiting between attempts respects context cancellation.
+//
+// The returned FetchResult (also on failure) reports the number of attempts made.
+type RetryFetcher struct {
+	Fetcher           HTTPFetcher   // Underlying fetcher
+	MaxAttempts       int           // Total attempts including the first one (min 1)
+	InitialBackoff    time.Duration // Delay before the first retry
+	MaxBackoff        time.Duration // Upper bound for the exponential backoff
+	RetryableStatuses []int         // HTTP status codes that trigger a retry
+}
+
+// NewRetryFetcher wraps fetcher with retries using the default retryable status codes.
+func NewRetryFetcher(fetcher HTTPFetcher, maxAttempts int, initialBackoff, maxBackoff time.Duration) *RetryFetcher {
+	return &RetryFetcher{
+		Fetcher:           fetcher,
+		MaxAttempts:       maxAttempts,
+		InitialBackoff:    initialBackoff,
+		MaxBackoff:        maxBackoff,
+		RetryableStatuses: DefaultRetryableStatuses,
+	}
+}
+
+// Fetch performs the request, retrying transient failures.
+func (r *RetryFetcher) Fetch(ctx context.Context, url string) (*FetchResult, error) {
+	maxAttempts := max(r.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		result, err := r.Fetcher.Fetch(ctx, url)
+		if err == nil {
+			return withAttempts(result, attempt), nil
+		}
+
+		if attempt >= maxAttempts || ctx.Err() != nil || !r.retryable(err) {
+			return withAttempts(result, attempt), err
+		}
+
+		delay := r.backoff(attempt)
+		if retryAfter, ok := retryAfterDelay(err); ok && retryAfter > delay {
+			if r.MaxBackoff > 0 && retryAfter > r.MaxBackoff {
+				return withAttempts(result, attempt), err
+			}
+			delay = retryAfter
+		}
+
+		if waitErr := sleepContext(ctx, delay); waitErr != nil {
+			return withAttempts(result, attempt), err
+		}
+	}
+}
+
+// withAttempts records the attempt count on result, creating it if necessary.
+func withAttempts(result *FetchResult, attempts int) *FetchResult {
+	if result == nil {
+		result = &FetchResult{}
+	}
+	result.Attempts = attempts
+	return result
+}
+
+// retryable reports whether err is a transient failure worth retrying.
+func (r *RetryFetcher) retryable(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return slices.Contains(r.RetryableStatuses, statusErr.StatusCode)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// backoff returns the delay before the retry following the given attempt:
+// InitialBackoff doubled per attempt, capped at MaxBackoff, with "equal jitter"
+// (half the delay is fixed, the other half random) to avoid synchronized retries.
+func (r *RetryFetcher) backoff(attempt int) time.Duration {
+	delay := r.InitialBackoff
+	for i := 1; i < attempt && (r.MaxBackoff <= 0 || delay < r.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if r.MaxBackoff > 0 && delay > r.MaxBackoff {
+		delay = r.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(delay-half)+1))
+}
+
+// retryAfterDelay extracts the Retry-After header (seconds or HTTP date) from an HTTP status error.
+func retryAfterDelay(err error) (time.Duration, bool) {
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.Header == nil {
+		return 0, false
+	}
+
+	value := statusErr.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, convErr := strconv.Atoi(value); convErr == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, parseErr := http.ParseTime(value); parseErr == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext waits for the given duration or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,120 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-scraper/core"
+)
+
+// flakyServer fails the first failures requests with the given status code.
+func flakyServer(failures int32, status int, header map[string]string) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			for k, v := range header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte("<html><title>OK</title></html>"))
+	}))
+	return server, &requests
+}
+
+func TestRetryFetcher_RetriesTransientStatus(t *testing.T) {
+	server, requests := flakyServer(2, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	fetcher := core.NewRetryFetcher(core.NewFetcher(2*time.Second, "UserAgent"), 3, time.Millisecond, 5*time.Millisecond)
+	result, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Attempts != 3 || requests.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d (server saw %d)", result.Attempts, requests.Load())
+	}
+}
+
+func TestRetryFetcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	server, requests := flakyServer(10, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	fetcher := core.NewRetryFetcher(core.NewFetcher(2*time.Second, "UserAgent"), 2, time.Millisecond, 5*time.Millisecond)
+	result, err := fetcher.Fetch(context.Background(), server.URL)
+
+	var statusErr *core.HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected HTTP 502 error, got %v", err)
+	}
+	if result == nil || result.Attempts != 2 || requests.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %+v (server saw %d)", result, requests.Load())
+	}
+}
+
+func TestRetryFetcher_DoesNotRetryPermanentStatus(t *testing.T) {
+	server, requests := flakyServer(10, http.StatusNotFound, nil)
+	defer server.Close()
+
+	fetcher := core.NewRetryFetcher(core.NewFetcher(2*time.Second, "UserAgent"), 3, time.Millisecond, 5*time.Millisecond)
+	if _, err := fetcher.Fetch(context.Background(), server.URL); err == nil {
+		t.Fatal("expected error for 404 response")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected a single request for 404, got %d", requests.Load())
+	}
+}
+
+func TestRetryFetcher_HonorsRetryAfter(t *testing.T) {
+	server, _ := flakyServer(1, http.StatusTooManyRequests, map[string]string{"Retry-After": "1"})
+	defer server.Close()
+
+	fetcher := core.NewRetryFetcher(core.NewFetcher(2*time.Second, "UserAgent"), 2, time.Millisecond, 2*time.Second)
+
+	start := time.Now()
+	if _, err := fetcher.Fetch(context.Background(), server.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected Retry-After of 1s to be honored, retried after %v", elapsed)
+	}
+}
+
+func TestRetryFetcher_GivesUpOnRetryAfterBeyondMaxBackoff(t *testing.T) {
+	server, requests := flakyServer(1, http.StatusTooManyRequests, map[string]string{"Retry-After": "86400"})
+	defer server.Close()
+
+	fetcher := core.NewRetryFetcher(core.NewFetcher(2*time.Second, "UserAgent"), 3, time.Millisecond, 50*time.Millisecond)
+
+	start := time.Now()
+	result, err := fetcher.Fetch(context.Background(), server.URL)
+	var statusErr *core.HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected HTTP 429 error, got %v", err)
+	}
+	if result.Attempts != 1 || requests.Load() != 1 || time.Since(start) > time.Second {
+		t.Errorf("expected to give up after one attempt, got %d attempts in %v", result.Attempts, time.Since(start))
+	}
+}
+
+func TestRetryFetcher_StopsOnContextCancel(t *testing.T) {
+	server, requests := flakyServer(10, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	fetcher := core.NewRetryFetcher(core.NewFetcher(2*time.Second, "UserAgent"), 5, time.Second, 5*time.Second)
+	if _, err := fetcher.Fetch(ctx, server.URL); err == nil {
+		t.Fatal("expected error")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected backoff wait to be aborted by context, got %d requests", requests.Load())
+	}
+}
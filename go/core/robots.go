@@ -0,0 +1,374 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-scraper/models"
+)
+
+const (
+	// RobotsDisallowedReason is the machine-readable prefix of Page.Error for pages
+	// that were not fetched because robots.txt disallows them.
+	RobotsDisallowedReason = string(models.ErrorKindRobotsDisallowed)
+	// robotsPath is the well-known location of the robots exclusion file
+	robotsPath = "/robots.txt"
+	// robotsFetchTimeout bounds the download of one robots.txt file
+	robotsFetchTimeout = 30 * time.Second
+	// DefaultRobotsRetryDelay is how long a host whose robots.txt could not be
+	// retrieved stays disallowed before the download is retried
+	DefaultRobotsRetryDelay = time.Minute
+)
+
+// ErrDisallowedByRobots is returned when a site's robots.txt forbids fetching a URL.
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// ErrRobotsUnavailable is wrapped, together with ErrDisallowedByRobots, by the errors
+// of requests to a host disallowed because its robots.txt failed with a server error
+// rather than because a rule matched.
+var ErrRobotsUnavailable = errors.New("robots.txt unavailable")
+
+// RobotsRules holds the robots.txt directives that apply to one user agent.
+// The zero value allows everything.
+type RobotsRules struct {
+	rules      []robotsRule
+	CrawlDelay time.Duration // Minimum delay between requests to the host (0 = none)
+}
+
+// robotsRule is a single Allow or Disallow path pattern.
+type robotsRule struct {
+	pattern string
+	allow   bool
+}
+
+// robotsGroup collects the directives following one or more User-agent lines.
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// ParseRobots parses a robots.txt file and returns the rules for userAgent.
+// The group whose User-agent value is the longest match within userAgent
+// (case-insensitive) is selected; if none matches, the "*" group applies.
+// Groups naming the same agent are merged. Unknown directives are ignored.
+func ParseRobots(data []byte, userAgent string) *RobotsRules {
+	var groups []*robotsGroup
+	var current *robotsGroup
+	lastWasAgent := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive User-agent lines share the same group
+			if current == nil || !lastWasAgent {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			lastWasAgent = true
+			continue
+		case "allow", "disallow":
+			// An empty Disallow means "allow everything" and adds no rule
+			if current != nil && value != "" {
+				current.rules = append(current.rules, robotsRule{pattern: value, allow: key == "allow"})
+			}
+		case "crawl-delay":
+			if current != nil {
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					current.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+		lastWasAgent = false
+	}
+
+	return selectRobotsGroups(groups, strings.ToLower(userAgent))
+}
+
+// selectRobotsGroups merges the groups that best match the user agent into RobotsRules.
+func selectRobotsGroups(groups []*robotsGroup, userAgent string) *RobotsRules {
+	bestLength := -1
+	var selected []*robotsGroup
+	var wildcard []*robotsGroup
+
+	for _, group := range groups {
+		for _, agent := range group.agents {
+			if agent == "*" {
+				wildcard = append(wildcard, group)
+				continue
+			}
+			if agent == "" || !strings.Contains(userAgent, agent) {
+				continue
+			}
+			if len(agent) > bestLength {
+				bestLength = len(agent)
+				selected = []*robotsGroup{group}
+			} else if len(agent) == bestLength {
+				selected = append(selected, group)
+			}
+		}
+	}
+
+	if len(selected) == 0 {
+		selected = wildcard
+	}
+
+	rules := &RobotsRules{}
+	for _, group := range selected {
+		rules.rules = append(rules.rules, group.rules...)
+		if group.crawlDelay > rules.CrawlDelay {
+			rules.CrawlDelay = group.crawlDelay
+		}
+	}
+	return rules
+}
+
+// Allowed reports whether the URL path (including the query) may be fetched.
+// The longest matching pattern wins; on a tie Allow takes precedence.
+// Patterns support the "*" wildcard and a trailing "$" end anchor.
+func (r *RobotsRules) Allowed(path string) bool {
+	if r == nil || path == robotsPath {
+		return true
+	}
+
+	allowed := true
+	matchLength := -1
+	for _, rule := range r.rules {
+		if !matchRobotsPattern(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > matchLength || (len(rule.pattern) == matchLength && rule.allow) {
+			matchLength = len(rule.pattern)
+			allowed = rule.allow
+		}
+	}
+	return allowed
+}
+
+// matchRobotsPattern matches a robots.txt path pattern against a URL path.
+// Without wildcards a pattern is a simple prefix match.
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			// The final segment must match at the end of the path
+			return strings.HasSuffix(path[pos:], part)
+		}
+		idx := strings.Index(path[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+
+	return !anchored || pos == len(path)
+}
+
+// RobotsFetcher is an HTTPFetcher decorator that enforces robots.txt.
+// Each host's robots.txt is downloaded once (through the wrapped fetcher) and cached
+// for the lifetime of the RobotsFetcher, i.e. for one run. Disallowed URLs fail with
+// ErrDisallowedByRobots and Crawl-delay directives space out requests to the host.
+// A missing robots.txt (4xx status) leaves the host unrestricted. If the server
+// fails to deliver it (5xx status), the whole host is disallowed until the download
+// is retried after RetryDelay. If the host cannot be reached at all, requests to it
+// fail with the network error of the download until the retry.
+type RobotsFetcher struct {
+	Fetcher    HTTPFetcher   // Underlying fetcher for pages and robots.txt files
+	UserAgent  string        // User agent whose rules are evaluated
+	RetryDelay time.Duration // Time before an unavailable robots.txt is requested again
+
+	mu    sync.Mutex
+	hosts map[string]*robotsHost
+}
+
+// robotsHost caches the rules of one host and schedules requests for its crawl delay.
+type robotsHost struct {
+	ready   chan struct{} // closed once rules are loaded
+	rules   *RobotsRules
+	err     error     // why robots.txt is unavailable, if it is
+	network bool      // err is a network error: the host cannot be reached
+	expires time.Time // when an unavailable robots.txt is retried (zero = never)
+
+	mu   sync.Mutex
+	next time.Time // earliest start of the next request (crawl delay)
+}
+
+// NewRobotsFetcher wraps fetcher with robots.txt enforcement for userAgent.
+func NewRobotsFetcher(fetcher HTTPFetcher, userAgent string) *RobotsFetcher {
+	return &RobotsFetcher{
+		Fetcher:    fetcher,
+		UserAgent:  userAgent,
+		RetryDelay: DefaultRobotsRetryDelay,
+		hosts:      make(map[string]*robotsHost),
+	}
+}
+
+// Fetch checks the URL against the host's robots.txt, waits for any crawl delay and
+// then delegates to the wrapped fetcher.
+func (r *RobotsFetcher) Fetch(ctx context.Context, rawURL string) (*FetchResult, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		// Let the wrapped fetcher report malformed URLs
+		return r.Fetcher.Fetch(ctx, rawURL)
+	}
+
+	host, err := r.host(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if host.network {
+		return nil, fmt.Errorf("fetching robots.txt for %s: %w", rawURL, host.err)
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if !host.rules.Allowed(path) {
+		if host.err != nil {
+			return nil, fmt.Errorf("%w: %s (%w: %v)", ErrDisallowedByRobots, rawURL, ErrRobotsUnavailable, host.err)
+		}
+		return nil, fmt.Errorf("%w: %s (user agent %q)", ErrDisallowedByRobots, rawURL, r.UserAgent)
+	}
+
+	if err := host.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.Fetcher.Fetch(ctx, rawURL)
+}
+
+// host returns the cached robots entry for the URL's host, loading robots.txt on first
+// use and again once an unavailable robots.txt is due for a retry. Concurrent callers
+// for the same host wait for the first download instead of repeating it.
+func (r *RobotsFetcher) host(ctx context.Context, u *url.URL) (*robotsHost, error) {
+	key := strings.ToLower(u.Scheme + "://" + u.Host)
+
+	r.mu.Lock()
+	if r.hosts == nil {
+		r.hosts = make(map[string]*robotsHost)
+	}
+	host, ok := r.hosts[key]
+	if !ok || host.expired() {
+		host = &robotsHost{ready: make(chan struct{})}
+		r.hosts[key] = host
+		go r.load(ctx, host, key+robotsPath)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-host.ready:
+		return host, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// load downloads and parses robots.txt into host. The rules are shared by every request
+// to the host, so the download is detached from the cancellation and deadline of the
+// request that triggered it and bounded by its own timeout instead.
+func (r *RobotsFetcher) load(ctx context.Context, host *robotsHost, robotsURL string) {
+	defer close(host.ready)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), robotsFetchTimeout)
+	defer cancel()
+
+	// robots.txt is always a plain GET, regardless of the options of the page that triggered it
+	result, err := r.Fetcher.Fetch(WithRequestOptions(ctx, RequestOptions{}), robotsURL)
+
+	var statusErr *HTTPStatusError
+	switch {
+	case err == nil:
+		host.rules = ParseRobots(result.Body, r.UserAgent)
+	case errors.As(err, &statusErr) && statusErr.StatusCode < http.StatusInternalServerError:
+		// No robots.txt (e.g. 404 Not Found): no restrictions
+		host.rules = &RobotsRules{}
+	case statusErr != nil:
+		// Server error: assume a complete disallow until the retry
+		host.rules = &RobotsRules{rules: []robotsRule{{pattern: "/", allow: false}}}
+		host.err = err
+		host.expires = time.Now().Add(r.RetryDelay)
+	default:
+		// Network error: requests to the host report it until the retry
+		host.rules = &RobotsRules{}
+		host.err = err
+		host.network = true
+		host.expires = time.Now().Add(r.RetryDelay)
+	}
+}
+
+// expired reports whether the host's robots.txt was unavailable and is due for a retry.
+func (h *robotsHost) expired() bool {
+	select {
+	case <-h.ready:
+		return !h.expires.IsZero() && !time.Now().Before(h.expires)
+	default:
+		return false // still loading
+	}
+}
+
+// wait blocks until the host's crawl delay allows the next request.
+func (h *robotsHost) wait(ctx context.Context) error {
+	if h.rules.CrawlDelay <= 0 {
+		return nil
+	}
+
+	// Reserve the next slot so concurrent requests are spaced out as well
+	h.mu.Lock()
+	now := time.Now()
+	start := h.next
+	if start.Before(now) {
+		start = now
+	}
+	h.next = start.Add(h.rules.CrawlDelay)
+	h.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
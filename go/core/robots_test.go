@@ -0,0 +1,205 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-scraper/core"
+	"go-scraper/models"
+)
+
+const testRobots = `
+# Example robots.txt
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+
+User-agent: WebScraper
+User-agent: OtherBot
+Disallow: /scraper-only
+Crawl-delay: 0.05
+`
+
+func TestParseRobots_WildcardGroup(t *testing.T) {
+	rules := core.ParseRobots([]byte(testRobots), "SomeBrowser/1.0")
+
+	tests := []struct {
+		path    string
+		allowed bool
+	}{
+		{"/", true},
+		{"/private/secret", false},
+		{"/private/public/page", true},
+		{"/docs/file.pdf", false},
+		{"/docs/file.pdf?download=1", true},
+		{"/scraper-only", true},
+		{"/robots.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := rules.Allowed(tt.path); got != tt.allowed {
+				t.Errorf("Allowed(%q) = %v, want %v", tt.path, got, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestParseRobots_SpecificGroup(t *testing.T) {
+	rules := core.ParseRobots([]byte(testRobots), "WebScraper/1.0")
+
+	if rules.Allowed("/scraper-only/page") {
+		t.Error("expected /scraper-only to be disallowed for WebScraper")
+	}
+	if !rules.Allowed("/private/secret") {
+		t.Error("expected wildcard rules not to apply when a specific group matches")
+	}
+	if rules.CrawlDelay != 50*time.Millisecond {
+		t.Errorf("expected crawl delay of 50ms, got %v", rules.CrawlDelay)
+	}
+}
+
+func TestRobotsFetcher_Fetch(t *testing.T) {
+	var robotsRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsRequests.Add(1)
+			_, _ = io.WriteString(w, "User-agent: *\nDisallow: /blocked\nCrawl-delay: 0.05\n")
+			return
+		}
+		_, _ = io.WriteString(w, "<html><title>OK</title></html>")
+	}))
+	defer server.Close()
+
+	fetcher := core.NewRobotsFetcher(core.NewFetcher(2*time.Second, "UserAgent"), "UserAgent")
+
+	start := time.Now()
+	for _, path := range []string{"/a", "/b"} {
+		if _, err := fetcher.Fetch(context.Background(), server.URL+path); err != nil {
+			t.Fatalf("unexpected error for %s: %v", path, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected crawl delay to space out requests, took %v", elapsed)
+	}
+
+	_, err := fetcher.Fetch(context.Background(), server.URL+"/blocked/page")
+	if !errors.Is(err, core.ErrDisallowedByRobots) {
+		t.Fatalf("expected ErrDisallowedByRobots, got %v", err)
+	}
+
+	if got := robotsRequests.Load(); got != 1 {
+		t.Errorf("expected robots.txt to be fetched once, got %d", got)
+	}
+}
+
+func TestRobotsFetcher_MissingRobotsAllowsAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, "<html></html>")
+	}))
+	defer server.Close()
+
+	fetcher := core.NewRobotsFetcher(core.NewFetcher(2*time.Second, "UserAgent"), "UserAgent")
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/anything"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRobotsFetcher_UnavailableRobotsDisallowsUntilRetry(t *testing.T) {
+	var robotsFailing atomic.Bool
+	robotsFailing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			if robotsFailing.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			return
+		}
+		_, _ = io.WriteString(w, "<html></html>")
+	}))
+	defer server.Close()
+
+	fetcher := core.NewRobotsFetcher(core.NewFetcher(2*time.Second, "UserAgent"), "UserAgent")
+	fetcher.RetryDelay = 50 * time.Millisecond
+
+	_, err := fetcher.Fetch(context.Background(), server.URL+"/page")
+	if !errors.Is(err, core.ErrDisallowedByRobots) || !strings.Contains(err.Error(), "robots.txt unavailable") {
+		t.Fatalf("expected the host to be disallowed while robots.txt fails, got %v", err)
+	}
+
+	robotsFailing.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/page"); err != nil {
+		t.Fatalf("expected robots.txt to be retried after the delay, got %v", err)
+	}
+}
+
+func TestRobotsFetcher_UnreachableHostKeepsNetworkError(t *testing.T) {
+	// An address nobody listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	fetcher := core.NewRobotsFetcher(core.NewFetcher(2*time.Second, "UserAgent"), "UserAgent")
+	_, err = fetcher.Fetch(context.Background(), "http://"+addr+"/page")
+	if errors.Is(err, core.ErrDisallowedByRobots) {
+		t.Fatalf("expected the network error, got %v", err)
+	}
+	if kind := core.ClassifyError(err); kind != models.ErrorKindConnectionRefused {
+		t.Errorf("expected kind %s, got %s (%v)", models.ErrorKindConnectionRefused, kind, err)
+	}
+}
+
+func TestRobotsFetcher_LoadIsDetachedFromRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = io.WriteString(w, "User-agent: *\nDisallow: /blocked\n")
+			return
+		}
+		_, _ = io.WriteString(w, "<html></html>")
+	}))
+	defer server.Close()
+
+	fetcher := core.NewRobotsFetcher(core.NewFetcher(2*time.Second, "UserAgent"), "UserAgent")
+
+	// The first request gives up before robots.txt arrives
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := fetcher.Fetch(ctx, server.URL+"/page"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the first request to time out, got %v", err)
+	}
+
+	// Later requests still see the rules instead of an allow-all fallback
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/blocked"); !errors.Is(err, core.ErrDisallowedByRobots) {
+		t.Fatalf("expected ErrDisallowedByRobots, got %v", err)
+	}
+}
+
+func TestScraper_Scrape_RobotsDisallowed(t *testing.T) {
+	s := core.NewScraper(&MockFetcher{Err: core.ErrDisallowedByRobots})
+
+	page, err := s.Scrape(context.Background(), models.Target{URL: "https://example.com/private"})
+	if !errors.Is(err, core.ErrDisallowedByRobots) {
+		t.Fatalf("expected ErrDisallowedByRobots, got %v", err)
+	}
+	if !strings.HasPrefix(page.Error, core.RobotsDisallowedReason+":") {
+		t.Errorf("expected error reason prefix %q, got %q", core.RobotsDisallowedReason, page.Error)
+	}
+}
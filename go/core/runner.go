@@ -2,79 +2,123 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"go-scraper/models"
 	"go-scraper/ui"
 )
 
-// RunSequential scrapes URLs one at a time in sequential order.
+// RunSequential scrapes targets one at a time in sequential order.
 // Each URL is processed completely before moving to the next one.
 // Progress is tracked and displayed via the UI progress bar manager.
-// Context cancellation is respected - if ctx is cancelled, remaining URLs are skipped.
+// Context cancellation is respected - if ctx is cancelled (or a graceful stop is
+// requested, see WithGracefulStop), remaining URLs are not fetched and get a
+// placeholder Page with ErrorKindCancelled instead.
 // Returns a slice of Page results in the same order as the input URLs.
-func RunSequential(ctx context.Context, urls []string, scraper Scraper) []*models.Page {
-	pbm := ui.NewProgressBarManager(len(urls))
+// Options can stream each page to a ResultSink as it completes (see WithSink).
+func RunSequential(ctx context.Context, targets []models.Target, scraper Scraper, opts ...RunOption) []*models.Page {
+	options := newRunOptions(opts)
+
+	pbm := ui.NewProgressBarManager(len(targets))
 	defer pbm.StopRenderer()
 
-	results := make([]*models.Page, 0, len(urls))
+	var results []*models.Page
+	if !options.discard {
+		results = make([]*models.Page, 0, len(targets))
+	}
+	collect := func(page *models.Page) {
+		options.emit(page)
+		if !options.discard {
+			results = append(results, page)
+		}
+	}
+
+	for _, target := range targets {
+		if options.stopped(ctx) {
+			collect(cancelledPage(target))
+			continue
+		}
 
-	for _, url := range urls {
-		tracker := pbm.NewTracker(url, 2)
+		tracker := pbm.NewTracker(target.URL, 2)
 		tracker.Increment(1) // started
 
-		page, err := scraper.Scrape(ctx, url)
+		page, err := options.scrape(ctx, scraper, target)
 		if err != nil {
 			tracker.MarkAsErrored()
 		}
+		if page == nil {
+			page = failedPage(target, err)
+		}
 
-		results = append(results, page)
+		collect(page)
 		tracker.Increment(1) // finished
 	}
 
 	return results
 }
 
-// RunParallel scrapes URLs concurrently using a worker pool pattern.
+// RunParallel scrapes targets concurrently using a worker pool pattern.
 // Multiple workers process URLs in parallel up to the specified concurrency limit.
 // Progress is tracked and displayed via the UI progress bar manager.
-// Context cancellation is respected - workers will stop processing when ctx is cancelled.
-// Returns a slice of Page results (order may differ from input URLs due to parallelism).
+// Context cancellation is respected - workers stop fetching when ctx is cancelled (or a
+// graceful stop is requested, see WithGracefulStop) and the remaining URLs get a
+// placeholder Page with ErrorKindCancelled instead.
+// Returns a slice of Page results where results[i] belongs to targets[i].
+// Options can stream each page to a ResultSink as it completes (see WithSink).
 //
 // The concurrency parameter controls the maximum number of simultaneous workers.
 // If concurrency <= 0, it defaults to 1 (sequential processing).
-func RunParallel(ctx context.Context, urls []string, scraper Scraper, concurrency int) []*models.Page {
+func RunParallel(ctx context.Context, targets []models.Target, scraper Scraper, concurrency int, opts ...RunOption) []*models.Page {
+	options := newRunOptions(opts)
+
 	// Enforce minimal concurrency of 1
 	if concurrency <= 0 {
 		concurrency = 1
 	}
 
-	pbm := ui.NewProgressBarManager(len(urls))
+	pbm := ui.NewProgressBarManager(len(targets))
 	defer pbm.StopRenderer()
 
-	jobs := make(chan int, len(urls))
-	results := make(chan *models.Page, len(urls))
+	jobs := make(chan int, len(targets))
+	// Each worker writes only to the slots of the indices it received,
+	// so results keep the input order without further synchronization
+	var results []*models.Page
+	if !options.discard {
+		results = make([]*models.Page, len(targets))
+	}
+	emitter := newOrderedEmitter(options, concurrency)
+	collect := func(i int, page *models.Page) {
+		emitter.emit(i, page)
+		if !options.discard {
+			results[i] = page
+		}
+	}
 
 	// Define worker
-	worker := func(id int, jobs <-chan int, results chan<- *models.Page) {
+	worker := func(jobs <-chan int) {
 		for i := range jobs {
-			select {
-			case <-ctx.Done():
-				return // stop early if canceled
-			default:
-				url := urls[i]
-				tracker := pbm.NewTracker(url, 2)
-				tracker.Increment(1)
-
-				page, err := scraper.Scrape(ctx, url)
-				if err != nil {
-					tracker.MarkAsErrored()
-				}
-
-				tracker.Increment(1)
-
-				results <- page
+			emitter.wait(i)
+			target := targets[i]
+			if options.stopped(ctx) {
+				collect(i, cancelledPage(target))
+				continue // drain remaining jobs as cancelled
+			}
+
+			tracker := pbm.NewTracker(target.URL, 2)
+			tracker.Increment(1)
+
+			page, err := options.scrape(ctx, scraper, target)
+			if err != nil {
+				tracker.MarkAsErrored()
+			}
+			if page == nil {
+				page = failedPage(target, err)
 			}
+
+			tracker.Increment(1)
+			collect(i, page)
 		}
 	}
 
@@ -82,29 +126,42 @@ func RunParallel(ctx context.Context, urls []string, scraper Scraper, concurrenc
 	var wg sync.WaitGroup
 	for w := 1; w <= concurrency; w++ {
 		wg.Add(1)
-		go func(id int) {
+		go func() {
 			defer wg.Done()
-			worker(id, jobs, results)
-		}(w)
+			worker(jobs)
+		}()
 	}
 
 	// Send jobs
-	for i := range urls {
+	for i := range targets {
 		jobs <- i
 	}
 	close(jobs)
 
-	// Wait for workers and then close results
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	wg.Wait()
+	return results
+}
 
-	// Collect results
-	pages := make([]*models.Page, 0, len(urls))
-	for page := range results {
-		pages = append(pages, page)
+// cancelledPage returns the placeholder Page for a target that was skipped because the
+// run was cancelled before it could be fetched.
+func cancelledPage(target models.Target) *models.Page {
+	return &models.Page{
+		URL:       target.URL,
+		Tags:      target.Tags,
+		Error:     "skipped: run cancelled before the URL was fetched",
+		ErrorKind: models.ErrorKindCancelled,
+		TimeStamp: time.Now(),
 	}
+}
 
-	return pages
+// failedPage returns the placeholder Page for a target whose scraper returned no page,
+// so sinks and summaries never receive nil.
+func failedPage(target models.Target, err error) *models.Page {
+	return &models.Page{
+		URL:       target.URL,
+		Tags:      target.Tags,
+		Error:     fmt.Sprintf("scrape failed: %v", err),
+		ErrorKind: models.ErrorKindUnknown,
+		TimeStamp: time.Now(),
+	}
 }
@@ -2,19 +2,24 @@ package core_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go-scraper/core"
 	"go-scraper/models"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type MockScraper struct{}
 
-func (MockScraper) Scrape(_ context.Context, url string) (*models.Page, error) {
-	return &models.Page{URL: url, Title: "OK"}, nil
+func (MockScraper) Scrape(_ context.Context, target models.Target) (*models.Page, error) {
+	return &models.Page{URL: target.URL, Title: "OK"}, nil
 }
 
 func TestRunSequential(t *testing.T) {
-	urls := []string{"a", "b", "c"}
+	urls := models.NewTargets("a", "b", "c")
 	results := core.RunSequential(context.Background(), urls, MockScraper{})
 
 	if len(results) != len(urls) {
@@ -23,10 +28,322 @@ func TestRunSequential(t *testing.T) {
 }
 
 func TestRunParallel(t *testing.T) {
-	urls := []string{"a", "b", "c"}
+	urls := models.NewTargets("a", "b", "c")
 	results := core.RunParallel(context.Background(), urls, MockScraper{}, 2)
 
 	if len(results) != len(urls) {
 		t.Fatalf("expected %d results, got %d", len(urls), len(results))
 	}
 }
+
+// delayedScraper finishes earlier URLs last so unordered collection would be detected.
+type delayedScraper struct {
+	delays map[string]time.Duration
+}
+
+func (d delayedScraper) Scrape(_ context.Context, target models.Target) (*models.Page, error) {
+	time.Sleep(d.delays[target.URL])
+	return &models.Page{URL: target.URL, Title: "OK"}, nil
+}
+
+func TestRunParallel_PreservesInputOrder(t *testing.T) {
+	urls := models.NewTargets("a", "b", "c", "d")
+	scraper := delayedScraper{delays: map[string]time.Duration{
+		"a": 40 * time.Millisecond,
+		"b": 30 * time.Millisecond,
+		"c": 20 * time.Millisecond,
+		"d": 10 * time.Millisecond,
+	}}
+
+	results := core.RunParallel(context.Background(), urls, scraper, 4)
+
+	if len(results) != len(urls) {
+		t.Fatalf("expected %d results, got %d", len(urls), len(results))
+	}
+	for i, url := range models.TargetURLs(urls) {
+		if results[i].URL != url {
+			t.Errorf("results[%d]: expected %q, got %q", i, url, results[i].URL)
+		}
+	}
+}
+
+func TestRunners_CancelledURLsGetPlaceholders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	urls := models.NewTargets("a", "b", "c")
+	runners := map[string]func() []*models.Page{
+		"sequential": func() []*models.Page { return core.RunSequential(ctx, urls, MockScraper{}) },
+		"parallel":   func() []*models.Page { return core.RunParallel(ctx, urls, MockScraper{}, 2) },
+	}
+
+	for name, run := range runners {
+		t.Run(name, func(t *testing.T) {
+			results := run()
+			if len(results) != len(urls) {
+				t.Fatalf("expected %d results, got %d", len(urls), len(results))
+			}
+			for i, page := range results {
+				if page.URL != urls[i].URL || page.ErrorKind != models.ErrorKindCancelled || page.Error == "" {
+					t.Errorf("results[%d]: expected cancelled placeholder for %q, got %+v", i, urls[i].URL, page)
+				}
+			}
+		})
+	}
+}
+
+// nilScraper fails without returning a page.
+type nilScraper struct{}
+
+func (nilScraper) Scrape(_ context.Context, _ models.Target) (*models.Page, error) {
+	return nil, errors.New("boom")
+}
+
+func TestRunners_MissingPagesGetPlaceholders(t *testing.T) {
+	urls := models.NewTargets("a", "b")
+	runners := map[string]func(opts ...core.RunOption) []*models.Page{
+		"sequential": func(opts ...core.RunOption) []*models.Page {
+			return core.RunSequential(context.Background(), urls, nilScraper{}, opts...)
+		},
+		"parallel": func(opts ...core.RunOption) []*models.Page {
+			return core.RunParallel(context.Background(), urls, nilScraper{}, 2, opts...)
+		},
+	}
+
+	for name, run := range runners {
+		t.Run(name, func(t *testing.T) {
+			streamed := 0
+			results := run(core.WithSink(core.SinkFunc(func(page *models.Page) error {
+				if page != nil {
+					streamed++
+				}
+				return nil
+			})))
+			for i, page := range results {
+				if page == nil || page.URL != urls[i].URL || page.ErrorKind != models.ErrorKindUnknown || !strings.Contains(page.Error, "boom") {
+					t.Errorf("results[%d]: expected a failed placeholder, got %+v", i, page)
+				}
+			}
+			if streamed != len(urls) {
+				t.Errorf("expected the sink to receive %d pages, got %d", len(urls), streamed)
+			}
+		})
+	}
+}
+
+func TestRunners_StreamToSink(t *testing.T) {
+	urls := models.NewTargets("a", "b", "c")
+	runners := map[string]func(opts ...core.RunOption) []*models.Page{
+		"sequential": func(opts ...core.RunOption) []*models.Page {
+			return core.RunSequential(context.Background(), urls, MockScraper{}, opts...)
+		},
+		"parallel": func(opts ...core.RunOption) []*models.Page {
+			return core.RunParallel(context.Background(), urls, MockScraper{}, 3, opts...)
+		},
+	}
+
+	for name, run := range runners {
+		t.Run(name, func(t *testing.T) {
+			var streamed []string
+			sink := core.SinkFunc(func(page *models.Page) error {
+				streamed = append(streamed, page.URL)
+				return nil
+			})
+
+			results := run(core.WithSink(sink), core.DiscardResults())
+
+			if results != nil {
+				t.Errorf("expected no results to be kept in memory, got %d", len(results))
+			}
+			if len(streamed) != len(urls) {
+				t.Errorf("expected %d streamed pages, got %v", len(urls), streamed)
+			}
+		})
+	}
+}
+
+func TestRunParallel_SinkReceivesInputOrder(t *testing.T) {
+	urls := models.NewTargets("a", "b", "c", "d")
+	scraper := delayedScraper{delays: map[string]time.Duration{
+		"a": 40 * time.Millisecond,
+		"b": 30 * time.Millisecond,
+		"c": 20 * time.Millisecond,
+		"d": 10 * time.Millisecond,
+	}}
+
+	var streamed []string
+	sink := core.SinkFunc(func(page *models.Page) error {
+		streamed = append(streamed, page.URL)
+		return nil
+	})
+	core.RunParallel(context.Background(), urls, scraper, 4, core.WithSink(sink))
+
+	if len(streamed) != len(urls) {
+		t.Fatalf("expected %d streamed pages, got %v", len(urls), streamed)
+	}
+	for i, url := range models.TargetURLs(urls) {
+		if streamed[i] != url {
+			t.Errorf("streamed[%d]: expected %q, got %q", i, url, streamed[i])
+		}
+	}
+}
+
+// blockingScraper holds the first URL until released and counts the started scrapes.
+type blockingScraper struct {
+	first   string
+	release chan struct{}
+	started *atomic.Int32
+}
+
+func (b blockingScraper) Scrape(_ context.Context, target models.Target) (*models.Page, error) {
+	b.started.Add(1)
+	if target.URL == b.first {
+		<-b.release
+	}
+	return &models.Page{URL: target.URL, Title: "OK"}, nil
+}
+
+func TestRunParallel_SlowURLBoundsBufferedPages(t *testing.T) {
+	urls := make([]string, 200)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("u%d", i)
+	}
+	scraper := blockingScraper{first: "u0", release: make(chan struct{}), started: &atomic.Int32{}}
+
+	streamed := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		core.RunParallel(context.Background(), models.NewTargets(urls...), scraper, 2,
+			core.WithSink(core.SinkFunc(func(*models.Page) error {
+				streamed++
+				return nil
+			})), core.DiscardResults())
+	}()
+
+	// While the first URL hangs, the other worker may only run a bounded distance ahead
+	time.Sleep(100 * time.Millisecond)
+	if started := scraper.started.Load(); started >= int32(len(urls)) {
+		t.Errorf("expected workers to wait for the slow URL, but all %d URLs were started", started)
+	}
+
+	close(scraper.release)
+	<-done
+	if streamed != len(urls) {
+		t.Errorf("expected %d streamed pages, got %d", len(urls), streamed)
+	}
+}
+
+// stoppingScraper requests a graceful stop while its first scrape is in flight.
+type stoppingScraper struct {
+	stop context.CancelFunc
+}
+
+func (s stoppingScraper) Scrape(ctx context.Context, target models.Target) (*models.Page, error) {
+	s.stop()
+	if ctx.Err() != nil {
+		return &models.Page{URL: target.URL, Error: "aborted", ErrorKind: models.ErrorKindCancelled}, ctx.Err()
+	}
+	return &models.Page{URL: target.URL, Title: "OK"}, nil
+}
+
+func TestRunners_GracefulStopFinishesInFlight(t *testing.T) {
+	urls := models.NewTargets("a", "b", "c")
+	runners := map[string]func(scraper core.Scraper, opts ...core.RunOption) []*models.Page{
+		"sequential": func(scraper core.Scraper, opts ...core.RunOption) []*models.Page {
+			return core.RunSequential(context.Background(), urls, scraper, opts...)
+		},
+		"parallel": func(scraper core.Scraper, opts ...core.RunOption) []*models.Page {
+			return core.RunParallel(context.Background(), urls, scraper, 1, opts...)
+		},
+	}
+
+	for name, run := range runners {
+		t.Run(name, func(t *testing.T) {
+			stop, cancel := context.WithCancel(context.Background())
+			results := run(stoppingScraper{stop: cancel}, core.WithGracefulStop(stop))
+
+			if len(results) != len(urls) {
+				t.Fatalf("expected %d results, got %d", len(urls), len(results))
+			}
+			if !results[0].Success() {
+				t.Errorf("expected the in-flight scrape to finish, got %+v", results[0])
+			}
+			for _, page := range results[1:] {
+				if page.ErrorKind != models.ErrorKindCancelled {
+					t.Errorf("expected cancelled placeholder, got %+v", page)
+				}
+			}
+		})
+	}
+}
+
+// hangingScraper ignores its context, like a worker stuck parsing a huge body.
+type hangingScraper struct {
+	release chan struct{}
+}
+
+func (h hangingScraper) Scrape(_ context.Context, target models.Target) (*models.Page, error) {
+	if target.URL == "slow" {
+		<-h.release
+	}
+	return &models.Page{URL: target.URL, Title: "OK"}, nil
+}
+
+// contextScraper blocks until its context is done and reports that as cancellation,
+// like DefaultScraper does for requests interrupted by the context.
+type contextScraper struct{}
+
+func (contextScraper) Scrape(ctx context.Context, target models.Target) (*models.Page, error) {
+	<-ctx.Done()
+	return &models.Page{URL: target.URL, Error: "fetch failed", ErrorKind: models.ErrorKindCancelled}, ctx.Err()
+}
+
+func TestRunners_URLTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	tests := []struct {
+		name    string
+		scraper core.Scraper
+	}{
+		{"IgnoresContext", hangingScraper{release: release}},
+		{"RespectsContext", contextScraper{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := models.NewTargets("slow", "fast")
+			start := time.Now()
+			results := core.RunParallel(context.Background(), urls, tt.scraper, 1, core.WithURLTimeout(50*time.Millisecond))
+
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("worker was held for %v despite the per-URL deadline", elapsed)
+			}
+			if results[0].ErrorKind != models.ErrorKindDeadline || results[0].Error == "" {
+				t.Errorf("expected deadline error for slow URL, got %+v", results[0])
+			}
+			if tt.name == "IgnoresContext" && !results[1].Success() {
+				t.Errorf("expected next URL to be scraped, got %+v", results[1])
+			}
+		})
+	}
+}
+
+func TestRunners_TargetTimeoutOverridesURLTimeout(t *testing.T) {
+	targets := []models.Target{{URL: "slow", TimeoutSeconds: 1, Tags: []string{"slow"}}, {URL: "fast"}}
+
+	results := core.RunSequential(context.Background(), targets, contextScraper{}, core.WithURLTimeout(20*time.Millisecond))
+
+	for _, page := range results {
+		if page.ErrorKind != models.ErrorKindDeadline {
+			t.Fatalf("expected deadline error, got %+v", page)
+		}
+	}
+	if !strings.Contains(results[0].Error, "1s") || !strings.Contains(results[1].Error, "20ms") {
+		t.Errorf("expected per-target and global deadlines, got %q and %q", results[0].Error, results[1].Error)
+	}
+	if len(results[0].Tags) != 1 {
+		t.Errorf("expected the deadline page to keep its tags, got %+v", results[0])
+	}
+}
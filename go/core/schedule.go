@@ -0,0 +1,217 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears bounds the search for the next match of a cron schedule, so
+// expressions that can never match (e.g. February 30) do not loop forever.
+const cronSearchYears = 5
+
+// Schedule decides when a recurring job runs next.
+type Schedule interface {
+	// Next returns the first run time after t.
+	Next(t time.Time) time.Time
+}
+
+// IntervalSchedule runs a job at a fixed delay after the previous run.
+type IntervalSchedule time.Duration
+
+// Next returns t plus the interval.
+func (s IntervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// CronSchedule runs a job at the times matching a standard five-field cron
+// expression: minute, hour, day of month, month and day of week.
+type CronSchedule struct {
+	minute, hour, day, month, weekday uint64 // Bit i is set when value i matches
+
+	// Like cron, a job runs when either the day of month or the day of week matches
+	// if both fields are restricted (do not start with "*"), and when both match otherwise
+	dayRestricted, weekdayRestricted bool
+}
+
+// cronField describes the value range of a cron expression field.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 and 7 are Sunday
+}
+
+// cronMacros are the supported shorthands for common schedules.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a cron expression with five space-separated fields
+// (minute hour day-of-month month day-of-week). Each field is "*", a value, a range
+// "a-b" or a comma-separated list of them, optionally with a step ("*/15", "1-5/2").
+// The macros @yearly, @monthly, @weekly, @daily and @hourly are accepted as well.
+// Times are evaluated in the location of the time passed to Next.
+func ParseCron(expr string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(parts))
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		bits[i] = set
+	}
+
+	// Sunday may be written as 0 or 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	schedule := &CronSchedule{
+		minute:            bits[0],
+		hour:              bits[1],
+		day:               bits[2],
+		month:             bits[3],
+		weekday:           bits[4],
+		dayRestricted:     !strings.HasPrefix(parts[2], "*"),
+		weekdayRestricted: !strings.HasPrefix(parts[4], "*"),
+	}
+	if schedule.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("invalid cron expression %q: never matches", expr)
+	}
+	return schedule, nil
+}
+
+// parseCronField converts one field of a cron expression into a bit set of matching values.
+func parseCronField(value string, field cronField) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step %q", field.name, stepPart)
+			}
+			step = n
+		}
+
+		low, high := field.min, field.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseCronValue(from, field); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(to, field); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("%s: invalid range %q", field.name, rangePart)
+			}
+		default:
+			n, err := parseCronValue(rangePart, field)
+			if err != nil {
+				return 0, err
+			}
+			low = n
+			if !hasStep {
+				high = n // "5/10" means every 10th value starting at 5
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// parseCronValue parses a single number of a cron field and checks its range.
+func parseCronValue(value string, field cronField) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid value %q", field.name, value)
+	}
+	if n < field.min || n > field.max {
+		return 0, fmt.Errorf("%s: value %d out of range %d-%d", field.name, n, field.min, field.max)
+	}
+	return n, nil
+}
+
+// Next returns the first minute after t that matches the schedule, or the zero
+// time if there is none within the next few years.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(cronSearchYears, 0, 0)
+
+	for next.Before(limit) {
+		switch {
+		case s.month&(1<<int(next.Month())) == 0:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<next.Hour()) == 0:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<next.Minute()) == 0:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay reports whether the day of month and day of week of t match.
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	day := s.day&(1<<t.Day()) != 0
+	weekday := s.weekday&(1<<int(t.Weekday())) != 0
+	if s.dayRestricted && s.weekdayRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// NewSchedule returns the cron schedule of expr if it is not empty, and otherwise
+// a schedule with the given fixed interval.
+func NewSchedule(interval time.Duration, expr string) (Schedule, error) {
+	if strings.TrimSpace(expr) != "" {
+		schedule, err := ParseCron(expr)
+		if err != nil {
+			return nil, err
+		}
+		return schedule, nil
+	}
+	if interval <= 0 {
+		return nil, errors.New("an interval or a cron expression is required")
+	}
+	return IntervalSchedule(interval), nil
+}
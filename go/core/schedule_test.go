@@ -0,0 +1,117 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-scraper/core"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Friday, 2026-10-16 10:17:30 UTC
+	from := time.Date(2026, 10, 16, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 16, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)},
+		{"0 8-18/2 * * *", time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)},
+		{"5,45 10 * * *", time.Date(2026, 10, 16, 10, 45, 0, 0, time.UTC)},
+		{"0 0 * * 1-5", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Day of month and day of week both restricted: either one matches
+		{"0 0 1 * 6", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := core.ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next := schedule.Next(from); !next.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, next)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_NextUsesLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	schedule, err := core.ParseCron("0 9 * * *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	next := schedule.Next(time.Date(2026, 10, 16, 8, 0, 0, 0, loc))
+	if expected := time.Date(2026, 10, 16, 9, 0, 0, 0, loc); !next.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, next)
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	tests := []struct {
+		expr    string
+		message string
+	}{
+		{"* * * *", "expected 5 fields, got 4"},
+		{"60 * * * *", "minute: value 60 out of range 0-59"},
+		{"* 24 * * *", "hour: value 24 out of range 0-23"},
+		{"* * 0 * *", "day of month: value 0 out of range 1-31"},
+		{"* * * 13 *", "month: value 13 out of range 1-12"},
+		{"* * * * 8", "day of week: value 8 out of range 0-7"},
+		{"*/0 * * * *", `minute: invalid step "0"`},
+		{"5-1 * * * *", `minute: invalid range "5-1"`},
+		{"x * * * *", `minute: invalid value "x"`},
+		{"0 0 30 2 *", "never matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := core.ParseCron(tt.expr)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("expected error containing %q, got %q", tt.message, err)
+			}
+		})
+	}
+}
+
+func TestNewSchedule(t *testing.T) {
+	from := time.Date(2026, 10, 16, 10, 17, 30, 0, time.UTC)
+
+	interval, err := core.NewSchedule(10*time.Minute, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next := interval.Next(from); !next.Equal(from.Add(10 * time.Minute)) {
+		t.Errorf("expected the interval after %v, got %v", from, next)
+	}
+
+	// A cron expression takes precedence over the interval
+	cron, err := core.NewSchedule(10*time.Minute, "@hourly")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next := cron.Next(from); !next.Equal(time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the next full hour, got %v", next)
+	}
+
+	if _, err := core.NewSchedule(0, ""); err == nil {
+		t.Error("expected an error without interval and cron expression")
+	}
+	if schedule, err := core.NewSchedule(time.Minute, "bogus"); err == nil || schedule != nil {
+		t.Errorf("expected an error and no schedule, got %v, %v", schedule, err)
+	}
+}
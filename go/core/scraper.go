@@ -2,9 +2,12 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-scraper/models"
 	"io"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -17,27 +20,93 @@ import (
 //   - Return a Page object containing extracted data (title, links, images)
 //   - Populate the Page.Error field if scraping fails, rather than returning nil
 //   - Return both a Page and an error to provide structured data even on failure
+//   - Apply the per-URL options of the target and copy its tags to the Page
 type Scraper interface {
-	Scrape(ctx context.Context, url string) (*models.Page, error)
+	Scrape(ctx context.Context, target models.Target) (*models.Page, error)
+}
+
+// DefaultCaptureHeaders lists the response headers recorded on each page by default.
+var DefaultCaptureHeaders = []string{
+	"Server",
+	"Cache-Control",
+	"Content-Encoding",
+	"ETag",
+	"Last-Modified",
+	"X-Powered-By",
 }
 
 // DefaultScraper is the production implementation that combines HTTP fetching
 // with HTML parsing to extract structured data from web pages.
 type DefaultScraper struct {
-	Fetcher HTTPFetcher // HTTPFetcher implementation for retrieving page content
+	Fetcher        HTTPFetcher     // HTTPFetcher implementation for retrieving page content
+	CaptureHeaders []string        // Response headers recorded in Page.Response
+	Fields         *FieldExtractor // Custom field rules applied to every page (nil = none)
+	StoreText      bool            // Keep the main-content text in Page.Content (greatly increases result size)
+
+	// HostFields replaces Fields for pages on the given hosts and their subdomains.
+	// Keys are lowercase host names; the most specific match wins. Build the
+	// extractors with Fields.With so they include the global rules.
+	HostFields map[string]*FieldExtractor
 }
 
 // NewScraper creates a DefaultScraper with the provided HTTPFetcher.
 // The fetcher will be used to retrieve page content before parsing.
 func NewScraper(fetcher HTTPFetcher) *DefaultScraper {
-	return &DefaultScraper{Fetcher: fetcher}
+	return &DefaultScraper{Fetcher: fetcher, CaptureHeaders: DefaultCaptureHeaders}
 }
 
 // Scrape fetches a web page, parses its HTML content, and returns a Page model
-// containing the extracted title, links, and images. The Page.Error field is
-// populated if fetching or parsing fails. An error is also returned for
-// programmatic error handling.
-func (s *DefaultScraper) Scrape(ctx context.Context, url string) (*models.Page, error) {
+// containing the extracted title, links, and images. The request uses the target's
+// method and headers, the target's extraction rules are added to the configured
+// Fields (or HostFields), and the target's tags are copied to the Page. Links and images are resolved
+// to absolute URLs against the final URL after redirects. The Page.Error field is
+// populated if fetching or parsing fails, together with the classified ErrorKind
+// and HTTP status. The returned error is a *ScrapeError that matches the sentinel
+// errors of this package (ErrTimeout, ErrHTTPStatus, ...) via errors.Is.
+func (s *DefaultScraper) Scrape(ctx context.Context, target models.Target) (*models.Page, error) {
+	var page *models.Page
+	fields, err := s.fieldsFor(target.URL).With(target.Extract)
+	if err != nil {
+		page = &models.Page{
+			URL:       target.URL,
+			Error:     fmt.Sprintf("invalid extract rules: %v", err),
+			ErrorKind: models.ErrorKindUnknown,
+			TimeStamp: time.Now(),
+		}
+		err = newScrapeError(models.ErrorKindUnknown, target.URL, fmt.Errorf("invalid extract rules: %w", err))
+	} else {
+		ctx = WithRequestOptions(ctx, RequestOptions{Method: target.Method, Headers: target.Headers})
+		page, err = s.scrape(ctx, target.URL, fields)
+	}
+	page.Tags = target.Tags
+	return page, err
+}
+
+// fieldsFor returns the field extractor for the host of rawURL: the HostFields entry
+// of the host or its closest parent domain, or Fields if there is none.
+func (s *DefaultScraper) fieldsFor(rawURL string) *FieldExtractor {
+	if len(s.HostFields) == 0 {
+		return s.Fields
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return s.Fields
+	}
+	for host := strings.ToLower(u.Hostname()); host != ""; {
+		if fields, ok := s.HostFields[host]; ok {
+			return fields
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return s.Fields
+}
+
+// scrape fetches and parses a single URL; the request options are taken from ctx.
+func (s *DefaultScraper) scrape(ctx context.Context, url string, fields *FieldExtractor) (*models.Page, error) {
 	startTime := time.Now()
 
 	// Defensive: ensure fetcher is initialized
@@ -45,34 +114,73 @@ func (s *DefaultScraper) Scrape(ctx context.Context, url string) (*models.Page,
 		return &models.Page{
 			URL:       url,
 			Error:     "no fetcher configured",
+			ErrorKind: models.ErrorKindUnknown,
 			TimeStamp: startTime,
-		}, fmt.Errorf("scraper misconfiguration: no fetcher provided")
+		}, newScrapeError(models.ErrorKindUnknown, url, errors.New("scraper misconfiguration: no fetcher provided"))
 	}
 
-	body, err := s.Fetcher.Fetch(ctx, url)
+	fetched, err := s.Fetcher.Fetch(ctx, url)
+	attempts := 0
+	if fetched != nil {
+		attempts = fetched.Attempts
+	}
+	response := fetched.ResponseInfo(s.CaptureHeaders)
+
 	if err != nil {
+		scrapeErr := newScrapeError(ClassifyError(err), url, fmt.Errorf("failed to fetch %s: %w", url, err))
+		if ctx.Err() != nil && scrapeErr.Kind != models.ErrorKindHTTPStatus {
+			// The run was cancelled or hit its deadline while the request was in flight
+			scrapeErr.Kind = models.ErrorKindCancelled
+		}
+
+		message := fmt.Sprintf("fetch failed: %v", err)
+		if scrapeErr.Kind == models.ErrorKindRobotsDisallowed {
+			// Report robots.txt exclusions with a distinct, machine-readable reason
+			message = fmt.Sprintf("%s: %v", RobotsDisallowedReason, err)
+		}
+
 		return &models.Page{
-			URL:       url,
-			Error:     fmt.Sprintf("fetch failed: %v", err),
-			TimeStamp: startTime,
-		}, fmt.Errorf("failed to fetch %s: %w", url, err)
+			URL:        url,
+			Error:      message,
+			ErrorKind:  scrapeErr.Kind,
+			HTTPStatus: scrapeErr.StatusCode,
+			TimeStamp:  startTime,
+			Attempts:   attempts,
+			Response:   response,
+		}, scrapeErr
+	}
+
+	// Resolve relative references against the final URL after redirects
+	baseURL := fetched.FinalURL
+	if baseURL == "" {
+		baseURL = url
 	}
 
-	title, links, images, err := ParseHTML(bytesToReader(body))
+	parsed, err := ParsePageWithOptions(bytesToReader(fetched.Body), baseURL, ParseOptions{Fields: fields, StoreText: s.StoreText})
 	if err != nil {
 		return &models.Page{
 			URL:       url,
 			Error:     fmt.Sprintf("parse failed: %v", err),
+			ErrorKind: models.ErrorKindParse,
 			TimeStamp: startTime,
-		}, fmt.Errorf("failed to parse HTML from %s: %w", url, err)
+			Attempts:  attempts,
+			Response:  response,
+		}, newScrapeError(models.ErrorKindParse, url, fmt.Errorf("failed to parse HTML from %s: %w", url, err))
 	}
 
 	return &models.Page{
-		URL:       url,
-		Title:     title,
-		Links:     links,
-		Images:    images,
-		TimeStamp: time.Now(),
+		URL:        url,
+		Title:      parsed.Title,
+		Links:      parsed.Links,
+		Images:     parsed.Images,
+		OtherLinks: parsed.OtherLinks,
+		Fields:     parsed.Fields,
+		Metadata:   parsed.Metadata,
+		Content:    parsed.Content,
+		TimeStamp:  time.Now(),
+		Attempts:   attempts,
+		Response:   response,
+		FromCache:  fetched.FromCache,
 	}, nil
 }
 
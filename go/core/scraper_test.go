@@ -4,28 +4,33 @@ import (
 	"context"
 	"errors"
 	"go-scraper/core"
+	"go-scraper/models"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 // MockFetcher for testing
 type MockFetcher struct {
 	Response string
+	FinalURL string
 	Err      error
 }
 
-func (m *MockFetcher) Fetch(_ context.Context, _ string) ([]byte, error) {
+func (m *MockFetcher) Fetch(_ context.Context, _ string) (*core.FetchResult, error) {
 	if m.Err != nil {
 		return nil, m.Err
 	}
-	return []byte(m.Response), nil
+	return &core.FetchResult{Body: []byte(m.Response), FinalURL: m.FinalURL}, nil
 }
 
 func TestScraper_Scrape_Success(t *testing.T) {
 	html := `<html><head><title>Mock</title></head><body><a href="https://x.com"></a></body></html>`
 	s := core.NewScraper(&MockFetcher{Response: html})
 
-	page, err := s.Scrape(context.Background(), "https://example.com")
+	page, err := s.Scrape(context.Background(), models.Target{URL: "https://example.com"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -43,7 +48,7 @@ func TestScraper_Scrape_FetchError(t *testing.T) {
 	mockErr := errors.New("network down")
 	s := core.NewScraper(&MockFetcher{Err: mockErr})
 
-	page, err := s.Scrape(context.Background(), "https://example.com")
+	page, err := s.Scrape(context.Background(), models.Target{URL: "https://example.com"})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -54,3 +59,117 @@ func TestScraper_Scrape_FetchError(t *testing.T) {
 		t.Errorf("expected fetch error message, got: %s", page.Error)
 	}
 }
+
+func TestScraper_Scrape_ResolvesAgainstFinalURL(t *testing.T) {
+	html := `<html><body><a href="../about">About</a><img src="logo.png"></body></html>`
+	s := core.NewScraper(&MockFetcher{Response: html, FinalURL: "https://example.com/blog/post/"})
+
+	page, err := s.Scrape(context.Background(), models.Target{URL: "https://example.com/old"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(page.Links) != 1 || page.Links[0] != "https://example.com/blog/about" {
+		t.Errorf("unexpected links: %#v", page.Links)
+	}
+	if len(page.Images) != 1 || page.Images[0] != "https://example.com/blog/post/logo.png" {
+		t.Errorf("unexpected images: %#v", page.Images)
+	}
+}
+
+func TestScraper_Scrape_RecordsResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "test-server")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s := core.NewScraper(core.NewFetcher(2*time.Second, "UserAgent"))
+	page, _ := s.Scrape(context.Background(), models.Target{URL: server.URL})
+
+	if page.Response == nil {
+		t.Fatal("expected response metadata for HTTP error page")
+	}
+	if page.Response.StatusCode != http.StatusServiceUnavailable || page.Response.Headers["Server"] != "test-server" {
+		t.Errorf("unexpected response metadata: %+v", page.Response)
+	}
+}
+
+func TestScraper_Scrape_AppliesTargetOptions(t *testing.T) {
+	var method, userAgent, language string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, userAgent, language = r.Method, r.UserAgent(), r.Header.Get("Accept-Language")
+		_, _ = w.Write([]byte("<html><head><title>Hi</title></head></html>"))
+	}))
+	defer server.Close()
+
+	s := core.NewScraper(core.NewFetcher(2*time.Second, "UserAgent"))
+	page, err := s.Scrape(context.Background(), models.Target{
+		URL:     server.URL,
+		Method:  http.MethodPost,
+		Headers: map[string]string{"Accept-Language": "de", "User-Agent": "Custom"},
+		Tags:    []string{"news"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if method != http.MethodPost || userAgent != "Custom" || language != "de" {
+		t.Errorf("request options not applied: method %q, user agent %q, language %q", method, userAgent, language)
+	}
+	if len(page.Tags) != 1 || page.Tags[0] != "news" {
+		t.Errorf("expected tags to be copied to the page, got %v", page.Tags)
+	}
+}
+
+func TestScraper_Scrape_ExtractsFields(t *testing.T) {
+	html := `<html><body><h1>Widget</h1><span class="price">9.99</span></body></html>`
+	s := core.NewScraper(&MockFetcher{Response: html})
+	s.Fields, _ = core.NewFieldExtractor(map[string]models.ExtractRule{"name": {Selector: "h1"}})
+
+	page, err := s.Scrape(context.Background(), models.Target{
+		URL:     "https://example.com",
+		Extract: map[string]models.ExtractRule{"price": {Selector: ".price"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Fields["name"] != "Widget" || page.Fields["price"] != "9.99" {
+		t.Errorf("unexpected fields: %v", page.Fields)
+	}
+
+	page, err = s.Scrape(context.Background(), models.Target{
+		URL:     "https://example.com",
+		Extract: map[string]models.ExtractRule{"price": {Selector: "span["}},
+	})
+	if err == nil || !strings.Contains(page.Error, "invalid extract rules") {
+		t.Errorf("expected invalid extract rules error, got %v (page error %q)", err, page.Error)
+	}
+}
+
+func TestScraper_Scrape_UsesHostFields(t *testing.T) {
+	html := `<html><body><h1>Widget</h1><dl><dt>SKU</dt><dd>W-42</dd></dl></body></html>`
+	s := core.NewScraper(&MockFetcher{Response: html})
+	s.Fields, _ = core.NewFieldExtractor(map[string]models.ExtractRule{"name": {Selector: "h1"}})
+	shop, _ := s.Fields.With(map[string]models.ExtractRule{"sku": {XPath: "//dt[.='SKU']/following-sibling::dd"}})
+	s.HostFields = map[string]*core.FieldExtractor{"shop.com": shop}
+
+	tests := []struct {
+		url     string
+		wantSKU bool
+	}{
+		{"https://shop.com/p/1", true},
+		{"https://WWW.Shop.com/p/1", true},
+		{"https://other.com/p/1", false},
+		{"https://notshop.com/p/1", false},
+	}
+	for _, tt := range tests {
+		page, err := s.Scrape(context.Background(), models.Target{URL: tt.url})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := page.Fields["sku"]; ok != tt.wantSKU || page.Fields["name"] != "Widget" {
+			t.Errorf("%s: unexpected fields %v", tt.url, page.Fields)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-scraper/models"
+)
+
+// ResultSink receives each scraped page as soon as it is complete.
+// The runners serialize calls to Write, so implementations do not need to be
+// safe for concurrent use. A failing Write does not stop the run; sinks that
+// persist pages should keep the error and report it when they are closed.
+type ResultSink interface {
+	Write(page *models.Page) error
+}
+
+// SinkFunc adapts an ordinary function to the ResultSink interface.
+type SinkFunc func(page *models.Page) error
+
+// Write calls f(page).
+func (f SinkFunc) Write(page *models.Page) error { return f(page) }
+
+// MultiSink returns a ResultSink that writes each page to all sinks in order.
+// Every sink receives the page even if an earlier one fails; the first error is returned.
+func MultiSink(sinks ...ResultSink) ResultSink {
+	return SinkFunc(func(page *models.Page) error {
+		var firstErr error
+		for _, sink := range sinks {
+			if err := sink.Write(page); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	})
+}
+
+// RunOption configures optional behavior of RunSequential, RunParallel and RunCrawl.
+type RunOption func(*runOptions)
+
+// runOptions holds the settings applied by RunOption values.
+type runOptions struct {
+	sink    ResultSink
+	discard bool
+	stop    context.Context
+	timeout time.Duration
+
+	mu sync.Mutex // serializes sink writes from concurrent workers
+}
+
+// WithSink streams every page to sink as soon as it and all pages before it
+// have been scraped, so the sink receives pages in the order of the returned slice.
+func WithSink(sink ResultSink) RunOption {
+	return func(o *runOptions) { o.sink = sink }
+}
+
+// DiscardResults stops the runner from keeping pages in memory; it returns nil.
+// Combined with WithSink this bounds memory usage for large URL lists.
+func DiscardResults() RunOption {
+	return func(o *runOptions) { o.discard = true }
+}
+
+// WithGracefulStop lets the runner finish gracefully once stop is done: requests already
+// in flight complete (they are only aborted when the run context itself is cancelled),
+// while URLs that have not started yet get a cancelled placeholder Page.
+func WithGracefulStop(stop context.Context) RunOption {
+	return func(o *runOptions) { o.stop = stop }
+}
+
+// WithURLTimeout bounds the time spent on each URL, including retries and parsing.
+// A URL that exceeds the deadline yields a Page with ErrorKindDeadline and its worker
+// moves on to the next URL. Zero or negative values mean no limit.
+func WithURLTimeout(timeout time.Duration) RunOption {
+	return func(o *runOptions) { o.timeout = timeout }
+}
+
+// newRunOptions applies opts to the default settings.
+func newRunOptions(opts []RunOption) *runOptions {
+	o := &runOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// stopped reports whether no further URLs should be started, either because ctx is
+// done or because a graceful stop was requested.
+func (o *runOptions) stopped(ctx context.Context) bool {
+	return ctx.Err() != nil || (o.stop != nil && o.stop.Err() != nil)
+}
+
+// emit passes a completed page to the sink, if any.
+func (o *runOptions) emit(page *models.Page) {
+	if o.sink == nil || page == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_ = o.sink.Write(page) // sinks report persistent failures themselves
+}
+
+// emitWindowPerWorker bounds, per worker, how many pages may be buffered by an
+// orderedEmitter while an earlier page is still being scraped.
+const emitWindowPerWorker = 8
+
+// orderedEmitter forwards pages completed out of order by concurrent workers to the
+// sink in index order. Only pages finished ahead of a slower predecessor are buffered,
+// and workers wait (see wait) rather than run more than window pages ahead of it.
+type orderedEmitter struct {
+	options *runOptions
+	window  int
+
+	mu      sync.Mutex
+	moved   *sync.Cond // broadcast when next advances
+	next    int
+	pending map[int]*models.Page
+}
+
+// newOrderedEmitter creates an emitter whose first page has index 0 for the given
+// number of concurrent workers.
+func newOrderedEmitter(options *runOptions, concurrency int) *orderedEmitter {
+	e := &orderedEmitter{
+		options: options,
+		window:  max(concurrency, 1) * emitWindowPerWorker,
+		pending: make(map[int]*models.Page),
+	}
+	e.moved = sync.NewCond(&e.mu)
+	return e
+}
+
+// wait blocks until index i lies within the window following the oldest page not yet
+// emitted, so a single slow URL cannot make the buffer grow without limit.
+// The worker holding that oldest index never waits, so the window always advances.
+func (e *orderedEmitter) wait(i int) {
+	if e.options.sink == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i >= e.next+e.window {
+		e.moved.Wait()
+	}
+}
+
+// emit records the page at index i and flushes all consecutive pages that are ready.
+func (e *orderedEmitter) emit(i int, page *models.Page) {
+	if e.options.sink == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.pending[i] = page
+	for {
+		ready, ok := e.pending[e.next]
+		if !ok {
+			break
+		}
+		delete(e.pending, e.next)
+		e.next++
+		e.options.emit(ready)
+	}
+	e.moved.Broadcast()
+}
+
+// scrape scrapes a single target, enforcing the per-URL deadline if one is configured
+// (the target's own TimeoutSeconds takes precedence over WithURLTimeout).
+// The scraper runs in its own goroutine so that even work that ignores the context
+// (such as parsing a huge body) cannot hold the worker beyond the deadline.
+func (o *runOptions) scrape(ctx context.Context, scraper Scraper, target models.Target) (*models.Page, error) {
+	timeout := o.timeout
+	if target.TimeoutSeconds > 0 {
+		timeout = time.Duration(target.TimeoutSeconds) * time.Second
+	}
+	if timeout <= 0 {
+		return scraper.Scrape(ctx, target)
+	}
+
+	urlCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type scrapeResult struct {
+		page *models.Page
+		err  error
+	}
+	done := make(chan scrapeResult, 1) // buffered so an abandoned scrape can still finish
+	go func() {
+		page, err := scraper.Scrape(urlCtx, target)
+		done <- scrapeResult{page, err}
+	}()
+
+	var result scrapeResult
+	select {
+	case result = <-done:
+	case <-urlCtx.Done():
+		select {
+		case result = <-done:
+			// Finished right at the deadline: keep the result
+		default:
+			if ctx.Err() != nil {
+				return cancelledPage(target), newScrapeError(models.ErrorKindCancelled, target.URL, ctx.Err())
+			}
+			return deadlinePage(target, timeout, nil), newScrapeError(models.ErrorKindDeadline, target.URL, urlCtx.Err())
+		}
+	}
+
+	if ctx.Err() == nil && urlCtx.Err() != nil && result.page.HasError() &&
+		(result.page.ErrorKind == models.ErrorKindCancelled || result.page.ErrorKind == models.ErrorKindTimeout) {
+		// The failure was caused by the per-URL deadline, not the network or the run
+		return deadlinePage(target, timeout, result.page), newScrapeError(models.ErrorKindDeadline, target.URL, result.err)
+	}
+	return result.page, result.err
+}
+
+// deadlinePage marks page (or a new Page if nil) as failed by the per-URL deadline.
+func deadlinePage(target models.Target, timeout time.Duration, page *models.Page) *models.Page {
+	if page == nil {
+		page = &models.Page{URL: target.URL, Tags: target.Tags, TimeStamp: time.Now()}
+	}
+	page.Error = fmt.Sprintf("deadline exceeded: not completed within %v", timeout)
+	page.ErrorKind = models.ErrorKindDeadline
+	return page
+}
@@ -4,19 +4,12 @@ import (
 	"context"
 	"go-scraper/app"
 	"log"
-	"time"
-)
-
-const (
-	// AppTimeout defines the maximum execution time for the entire application
-	AppTimeout = 10 * time.Minute
+	"os"
 )
 
 func main() {
-	ctx, cancel := context.WithTimeout(context.Background(), AppTimeout)
-	defer cancel()
-
-	if err := app.Run(ctx); err != nil {
+	// Run and per-URL timeouts are configured in config.json (runTimeoutSeconds, perUrlTimeoutSeconds)
+	if err := app.Run(context.Background(), os.Args[1:]); err != nil {
 		log.Fatalf("Application error: %v", err)
 	}
 }
@@ -0,0 +1,18 @@
+package models
+
+// Content describes the readable main content of a page, without scripts,
+// styles, navigation and other boilerplate.
+type Content struct {
+	Text               string    `json:"text,omitempty"`     // Main-content text, one block per line (only stored if enabled)
+	WordCount          int       `json:"wordCount"`          // Number of words in the main content
+	ReadingTimeSeconds int       `json:"readingTimeSeconds"` // Estimated reading time at 200 words per minute
+	Headings           []Heading `json:"headings,omitempty"` // h1-h6 outline in document order
+	Language           string    `json:"language,omitempty"` // ISO 639-1 code detected from the text (empty if unsure)
+}
+
+// Heading is an entry of a page's heading outline. Level gives the hierarchy:
+// a heading belongs to the closest preceding heading with a lower level.
+type Heading struct {
+	Level int    `json:"level"` // 1 for <h1> through 6 for <h6>
+	Text  string `json:"text"`  // Text content of the heading
+}
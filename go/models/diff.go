@@ -0,0 +1,190 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ResultsDiff describes the changes between two scrape runs.
+type ResultsDiff struct {
+	Old     string       `json:"old"`     // Source of the older results (e.g. a file path)
+	New     string       `json:"new"`     // Source of the newer results
+	Added   []string     `json:"added"`   // URLs only present in the newer results
+	Removed []string     `json:"removed"` // URLs only present in the older results
+	Changed []PageChange `json:"changed"` // Pages present in both results that differ
+}
+
+// PageChange lists what changed on a page between two runs. Unchanged aspects are omitted.
+type PageChange struct {
+	URL           string                  `json:"url"`
+	Status        *ValueChange            `json:"status,omitempty"`        // Outcome, "success" or the error kind (see PageStatus)
+	Title         *ValueChange            `json:"title,omitempty"`         // Page title
+	LinksAdded    []string                `json:"linksAdded,omitempty"`    // Links only found in the newer run
+	LinksRemoved  []string                `json:"linksRemoved,omitempty"`  // Links only found in the older run
+	ImagesAdded   []string                `json:"imagesAdded,omitempty"`   // Images only found in the newer run
+	ImagesRemoved []string                `json:"imagesRemoved,omitempty"` // Images only found in the older run
+	Fields        map[string]*ValueChange `json:"fields,omitempty"`        // Extracted fields whose value changed, by name (empty if absent)
+	Metadata      bool                    `json:"metadata,omitempty"`      // Whether the <head> metadata changed
+	Content       bool                    `json:"content,omitempty"`       // Whether the main-content statistics or text changed
+}
+
+// ValueChange is the old and new value of a changed property.
+type ValueChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// HasChanges reports whether the diff found any difference.
+func (d *ResultsDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// PageStatus summarizes the outcome of a page: "success", or the error kind with
+// the HTTP status if there is one (e.g. "http_status 404").
+func PageStatus(page *Page) string {
+	if page.Success() {
+		return "success"
+	}
+	kind := page.ErrorKind
+	if kind == "" {
+		kind = ErrorKindUnknown
+	}
+	if page.HTTPStatus != 0 {
+		return fmt.Sprintf("%s %d", kind, page.HTTPStatus)
+	}
+	return string(kind)
+}
+
+// DiffPages compares two sets of results by URL. If a URL occurs several times
+// (e.g. in a resumed run), its last page is used. Link, image, field, metadata and
+// content changes are only reported when both pages were scraped successfully, so a
+// failing page does not show all of its links as removed. URLs are listed in alphabetical order.
+func DiffPages(oldPages, newPages []*Page) *ResultsDiff {
+	oldByURL := pagesByURL(oldPages)
+	newByURL := pagesByURL(newPages)
+	diff := &ResultsDiff{Added: []string{}, Removed: []string{}, Changed: []PageChange{}}
+
+	for url := range newByURL {
+		if _, ok := oldByURL[url]; !ok {
+			diff.Added = append(diff.Added, url)
+		}
+	}
+	for url, oldPage := range oldByURL {
+		newPage, ok := newByURL[url]
+		if !ok {
+			diff.Removed = append(diff.Removed, url)
+			continue
+		}
+		if change, changed := diffPage(oldPage, newPage); changed {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].URL < diff.Changed[j].URL })
+	return diff
+}
+
+// diffPage compares two pages of the same URL.
+func diffPage(oldPage, newPage *Page) (PageChange, bool) {
+	change := PageChange{URL: newPage.URL}
+	if oldStatus, newStatus := PageStatus(oldPage), PageStatus(newPage); oldStatus != newStatus {
+		change.Status = &ValueChange{Old: oldStatus, New: newStatus}
+	}
+	if oldPage.Success() && newPage.Success() {
+		if oldPage.Title != newPage.Title {
+			change.Title = &ValueChange{Old: oldPage.Title, New: newPage.Title}
+		}
+		change.LinksAdded, change.LinksRemoved = diffLists(oldPage.Links, newPage.Links)
+		change.ImagesAdded, change.ImagesRemoved = diffLists(oldPage.Images, newPage.Images)
+		change.Fields = diffFields(oldPage.Fields, newPage.Fields)
+		change.Metadata = !sameJSON(oldPage.Metadata, newPage.Metadata)
+		change.Content = !sameJSON(oldPage.Content, newPage.Content)
+	}
+
+	changed := change.Status != nil || change.Title != nil ||
+		len(change.LinksAdded) > 0 || len(change.LinksRemoved) > 0 ||
+		len(change.ImagesAdded) > 0 || len(change.ImagesRemoved) > 0 ||
+		len(change.Fields) > 0 || change.Metadata || change.Content
+	return change, changed
+}
+
+// diffFields returns the extracted fields whose value differs, or nil if none does.
+func diffFields(oldFields, newFields map[string]any) map[string]*ValueChange {
+	var changes map[string]*ValueChange
+	compare := func(name string) {
+		oldValue, newValue := fieldString(oldFields[name]), fieldString(newFields[name])
+		if oldValue == newValue {
+			return
+		}
+		if changes == nil {
+			changes = make(map[string]*ValueChange)
+		}
+		changes[name] = &ValueChange{Old: oldValue, New: newValue}
+	}
+	for name := range oldFields {
+		compare(name)
+	}
+	for name := range newFields {
+		compare(name)
+	}
+	return changes
+}
+
+// fieldString formats an extracted field value: strings as they are, lists as JSON.
+// Pages read back from a results file hold []any instead of []string, which formats
+// the same.
+func fieldString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// sameJSON reports whether a and b have the same JSON encoding, so a page read back
+// from a results file equals the page it was written from.
+func sameJSON(a, b any) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+// diffLists returns the entries only in newList and only in oldList, in list order.
+func diffLists(oldList, newList []string) (added, removed []string) {
+	oldSet := make(map[string]struct{}, len(oldList))
+	for _, item := range oldList {
+		oldSet[item] = struct{}{}
+	}
+	newSet := make(map[string]struct{}, len(newList))
+	for _, item := range newList {
+		newSet[item] = struct{}{}
+		if _, ok := oldSet[item]; !ok {
+			added = append(added, item)
+		}
+	}
+	for _, item := range oldList {
+		if _, ok := newSet[item]; !ok {
+			removed = append(removed, item)
+		}
+	}
+	return added, removed
+}
+
+// pagesByURL indexes pages by URL, keeping the last page of each URL.
+func pagesByURL(pages []*Page) map[string]*Page {
+	byURL := make(map[string]*Page, len(pages))
+	for _, page := range pages {
+		if page != nil {
+			byURL[page.URL] = page
+		}
+	}
+	return byURL
+}
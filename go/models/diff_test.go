@@ -0,0 +1,83 @@
+package models_test
+
+import (
+	"go-scraper/models"
+	"reflect"
+	"testing"
+)
+
+func TestDiffPages(t *testing.T) {
+	oldPages := []*models.Page{
+		{URL: "https://a.com", Title: "A", Links: []string{"https://a.com/1", "https://a.com/2"}},
+		{URL: "https://b.com", Title: "B"},
+		{URL: "https://c.com", Error: "boom", ErrorKind: models.ErrorKindHTTPStatus, HTTPStatus: 503},
+		{URL: "https://d.com", Title: "Old D"},
+		{URL: "https://d.com", Title: "D"}, // resumed run: the last page counts
+		{URL: "https://gone.com"},
+	}
+	newPages := []*models.Page{
+		{URL: "https://a.com", Title: "A2", Links: []string{"https://a.com/2", "https://a.com/3"}, Images: []string{"https://a.com/i.png"}},
+		{URL: "https://b.com", Error: "timeout", ErrorKind: models.ErrorKindTimeout},
+		{URL: "https://c.com", Title: "C"},
+		{URL: "https://d.com", Title: "D"},
+		{URL: "https://new.com"},
+	}
+
+	diff := models.DiffPages(oldPages, newPages)
+
+	expected := &models.ResultsDiff{
+		Added:   []string{"https://new.com"},
+		Removed: []string{"https://gone.com"},
+		Changed: []models.PageChange{
+			{
+				URL:          "https://a.com",
+				Title:        &models.ValueChange{Old: "A", New: "A2"},
+				LinksAdded:   []string{"https://a.com/3"},
+				LinksRemoved: []string{"https://a.com/1"},
+				ImagesAdded:  []string{"https://a.com/i.png"},
+			},
+			{URL: "https://b.com", Status: &models.ValueChange{Old: "success", New: "timeout"}},
+			{URL: "https://c.com", Status: &models.ValueChange{Old: "http_status 503", New: "success"}},
+		},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("expected %+v, got %+v", expected, diff)
+	}
+	if !diff.HasChanges() {
+		t.Error("expected HasChanges to be true")
+	}
+}
+
+func TestDiffPages_NoChanges(t *testing.T) {
+	pages := []*models.Page{{URL: "https://a.com", Title: "A", Links: []string{"https://a.com/1"}}}
+	if diff := models.DiffPages(pages, pages); diff.HasChanges() {
+		t.Errorf("expected no changes, got %+v", diff)
+	}
+}
+
+func TestDiffPages_FieldsMetadataAndContent(t *testing.T) {
+	oldPages := []*models.Page{
+		{URL: "https://shop.com/item", Fields: map[string]any{"price": "10", "tags": []string{"a"}, "old": "x"}},
+		{URL: "https://shop.com/about", Metadata: &models.Metadata{Description: "Old"}, Content: &models.Content{WordCount: 10}},
+		{URL: "https://shop.com/same", Fields: map[string]any{"tags": []string{"a", "b"}}},
+	}
+	newPages := []*models.Page{
+		{URL: "https://shop.com/item", Fields: map[string]any{"price": "12", "tags": []string{"a"}}},
+		{URL: "https://shop.com/about", Metadata: &models.Metadata{Description: "New"}, Content: &models.Content{WordCount: 12}},
+		// Read back from a results file, a list field holds []any
+		{URL: "https://shop.com/same", Fields: map[string]any{"tags": []any{"a", "b"}}},
+	}
+
+	diff := models.DiffPages(oldPages, newPages)
+
+	expected := []models.PageChange{
+		{URL: "https://shop.com/about", Metadata: true, Content: true},
+		{URL: "https://shop.com/item", Fields: map[string]*models.ValueChange{
+			"price": {Old: "10", New: "12"},
+			"old":   {Old: "x", New: ""},
+		}},
+	}
+	if !reflect.DeepEqual(diff.Changed, expected) {
+		t.Errorf("expected %+v, got %+v", expected, diff.Changed)
+	}
+}
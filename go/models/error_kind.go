@@ -0,0 +1,36 @@
+package models
+
+// ErrorKind classifies why scraping a page failed.
+// It is serialized with each page so downstream tooling can group failures
+// without parsing the free-form error message.
+type ErrorKind string
+
+const (
+	// ErrorKindTimeout indicates the request timed out.
+	ErrorKindTimeout ErrorKind = "timeout"
+	// ErrorKindDNS indicates the host name could not be resolved.
+	ErrorKindDNS ErrorKind = "dns"
+	// ErrorKindConnectionRefused indicates the server refused the TCP connection.
+	ErrorKindConnectionRefused ErrorKind = "connection_refused"
+	// ErrorKindTLS indicates a TLS handshake or certificate verification failure.
+	ErrorKindTLS ErrorKind = "tls"
+	// ErrorKindHTTPStatus indicates the server responded with a non-200 status code.
+	ErrorKindHTTPStatus ErrorKind = "http_status"
+	// ErrorKindParse indicates the response body could not be parsed as HTML.
+	ErrorKindParse ErrorKind = "parse"
+	// ErrorKindRobotsDisallowed indicates robots.txt forbids fetching the page.
+	ErrorKindRobotsDisallowed ErrorKind = "robots_disallowed"
+	// ErrorKindCancelled indicates the scrape was cancelled before it completed.
+	ErrorKindCancelled ErrorKind = "cancelled"
+	// ErrorKindDeadline indicates the per-URL deadline expired before scraping completed.
+	ErrorKindDeadline ErrorKind = "deadline"
+	// ErrorKindNetwork indicates any other network-level failure.
+	ErrorKindNetwork ErrorKind = "network"
+	// ErrorKindUnknown indicates a failure that could not be classified.
+	ErrorKindUnknown ErrorKind = "unknown"
+)
+
+// String returns the serialized representation of the ErrorKind.
+func (k ErrorKind) String() string {
+	return string(k)
+}
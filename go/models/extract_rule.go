@@ -0,0 +1,49 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ExtractRule describes how to extract a custom field from a page, using either a
+// CSS selector or an XPath expression. In configuration files a rule is either an
+// object or, as a shorthand, a string that extracts the text of the first match;
+// strings starting with "/" are XPath expressions, all others CSS selectors:
+//
+//	"extract": {
+//	  "price":  ".product .price",
+//	  "sku":    "//dt[text()='SKU']/following-sibling::dd[1]",
+//	  "author": {"selector": "meta[name=author]", "attr": "content"},
+//	  "tags":   {"xpath": "//a[@rel='tag']/@href", "multiple": true}
+//	}
+type ExtractRule struct {
+	Selector string `json:"selector,omitempty"` // CSS selector of the element(s) to extract
+	XPath    string `json:"xpath,omitempty"`    // XPath expression selecting elements, attributes or text, or computing a value
+	Attr     string `json:"attr,omitempty"`     // Attribute to read from selected elements; empty reads the element's text
+	Multiple bool   `json:"multiple,omitempty"` // Collect all matches as a list instead of the first one
+}
+
+// UnmarshalJSON accepts either a selector or XPath string, or a rule object.
+func (r *ExtractRule) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		var expr string
+		if err := json.Unmarshal(trimmed, &expr); err != nil {
+			return err
+		}
+		if strings.HasPrefix(strings.TrimSpace(expr), "/") {
+			*r = ExtractRule{XPath: expr}
+		} else {
+			*r = ExtractRule{Selector: expr}
+		}
+		return nil
+	}
+
+	type plain ExtractRule // without methods, to avoid recursion
+	var rule plain
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return err
+	}
+	*r = ExtractRule(rule)
+	return nil
+}
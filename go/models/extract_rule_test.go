@@ -0,0 +1,31 @@
+package models_test
+
+import (
+	"encoding/json"
+	"go-scraper/models"
+	"reflect"
+	"testing"
+)
+
+func TestExtractRule_UnmarshalJSON(t *testing.T) {
+	var rules map[string]models.ExtractRule
+	err := json.Unmarshal([]byte(`{
+		"price": ".price",
+		"sku": "//dd[@class='sku']",
+		"author": {"selector": "meta[name=author]", "attr": "content"},
+		"tags": {"selector": "a[rel=tag]", "multiple": true}
+	}`), &rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]models.ExtractRule{
+		"price":  {Selector: ".price"},
+		"sku":    {XPath: "//dd[@class='sku']"},
+		"author": {Selector: "meta[name=author]", Attr: "content"},
+		"tags":   {Selector: "a[rel=tag]", Multiple: true},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected %+v, got %+v", expected, rules)
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import "sort"
+
+// LinkType distinguishes the kinds of link check targets.
+type LinkType string
+
+const (
+	// LinkTypeLink is the target of an <a href> link.
+	LinkTypeLink LinkType = "link"
+	// LinkTypeImage is the source of an <img> element.
+	LinkTypeImage LinkType = "image"
+)
+
+// LinkTarget is a unique link or image URL found on scraped pages, together with
+// the pages that reference it.
+type LinkTarget struct {
+	URL     string   `json:"url"`               // Absolute URL of the target
+	Type    LinkType `json:"type"`              // How the target was first referenced
+	Sources []string `json:"sources,omitempty"` // URLs of the pages referencing the target, in scrape order
+}
+
+// LinkCheck is the outcome of checking a link target.
+type LinkCheck struct {
+	LinkTarget
+	Method     string    `json:"method,omitempty"`     // HTTP method of the final request (HEAD, or GET as fallback)
+	StatusCode int       `json:"statusCode,omitempty"` // HTTP status code of the final response (0 if none was received)
+	ErrorKind  ErrorKind `json:"errorKind,omitempty"`  // Classified failure category (empty if the target is reachable)
+	Error      string    `json:"error,omitempty"`      // Error message of the failure
+}
+
+// Broken reports whether the target is unreachable: it answered with a 4xx or 5xx
+// status, timed out or failed at the network level. Targets excluded by robots.txt
+// or skipped because the run was cancelled are not broken, they were not checked.
+func (c *LinkCheck) Broken() bool {
+	return c.ErrorKind != "" && !c.Skipped()
+}
+
+// Skipped reports whether the target was not checked: a robots.txt rule excludes it,
+// or the run was cancelled.
+func (c *LinkCheck) Skipped() bool {
+	return c.ErrorKind == ErrorKindRobotsDisallowed || c.ErrorKind == ErrorKindCancelled
+}
+
+// LinkReport summarizes a link check, listing the broken targets per source page.
+type LinkReport struct {
+	Checked int           `json:"checked"` // Number of unique targets checked
+	Broken  int           `json:"broken"`  // Number of unique targets found broken
+	Skipped int           `json:"skipped"` // Number of targets not checked (robots.txt or cancellation)
+	Sources []SourceLinks `json:"sources"` // Pages referencing broken targets, in alphabetical order
+}
+
+// SourceLinks lists the broken targets referenced by a page.
+type SourceLinks struct {
+	Page   string      `json:"page"`   // URL of the referencing page
+	Broken []LinkCheck `json:"broken"` // Broken targets in check order, without their sources
+}
+
+// NewLinkReport builds the report of the given checks. A broken target is listed
+// under every page that references it.
+func NewLinkReport(checks []LinkCheck) *LinkReport {
+	report := &LinkReport{Sources: []SourceLinks{}}
+	bySource := make(map[string][]LinkCheck)
+
+	for _, check := range checks {
+		switch {
+		case check.Skipped():
+			report.Skipped++
+			continue
+		case check.Broken():
+			report.Broken++
+			broken := check
+			broken.Sources = nil
+			for _, source := range check.Sources {
+				bySource[source] = append(bySource[source], broken)
+			}
+		}
+		report.Checked++
+	}
+
+	for page, broken := range bySource {
+		report.Sources = append(report.Sources, SourceLinks{Page: page, Broken: broken})
+	}
+	sort.Slice(report.Sources, func(i, j int) bool { return report.Sources[i].Page < report.Sources[j].Page })
+	return report
+}
@@ -0,0 +1,67 @@
+package models_test
+
+import (
+	"go-scraper/models"
+	"testing"
+)
+
+func TestLinkCheck_Broken(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   models.LinkCheck
+		broken  bool
+		skipped bool
+	}{
+		{"reachable", models.LinkCheck{StatusCode: 200}, false, false},
+		{"not found", models.LinkCheck{StatusCode: 404, ErrorKind: models.ErrorKindHTTPStatus}, true, false},
+		{"timeout", models.LinkCheck{ErrorKind: models.ErrorKindTimeout}, true, false},
+		{"robots", models.LinkCheck{ErrorKind: models.ErrorKindRobotsDisallowed}, false, true},
+		{"cancelled", models.LinkCheck{ErrorKind: models.ErrorKindCancelled}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check.Broken(); got != tt.broken {
+				t.Errorf("Broken() = %v, want %v", got, tt.broken)
+			}
+			if got := tt.check.Skipped(); got != tt.skipped {
+				t.Errorf("Skipped() = %v, want %v", got, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestNewLinkReport_GroupsBySource(t *testing.T) {
+	checks := []models.LinkCheck{
+		{LinkTarget: models.LinkTarget{URL: "https://a.com/ok", Sources: []string{"https://b.com"}}, StatusCode: 200},
+		{
+			LinkTarget: models.LinkTarget{URL: "https://a.com/gone", Type: models.LinkTypeLink, Sources: []string{"https://b.com", "https://a.com"}},
+			StatusCode: 404, ErrorKind: models.ErrorKindHTTPStatus,
+		},
+		{
+			LinkTarget: models.LinkTarget{URL: "https://slow.com/logo.png", Type: models.LinkTypeImage, Sources: []string{"https://b.com"}},
+			ErrorKind:  models.ErrorKindTimeout,
+		},
+		{LinkTarget: models.LinkTarget{URL: "https://a.com/private", Sources: []string{"https://a.com"}}, ErrorKind: models.ErrorKindRobotsDisallowed},
+	}
+
+	report := models.NewLinkReport(checks)
+
+	if report.Checked != 3 || report.Broken != 2 || report.Skipped != 1 {
+		t.Errorf("unexpected counts: checked %d, broken %d, skipped %d", report.Checked, report.Broken, report.Skipped)
+	}
+	if len(report.Sources) != 2 {
+		t.Fatalf("expected two source pages, got %+v", report.Sources)
+	}
+
+	first, second := report.Sources[0], report.Sources[1]
+	if first.Page != "https://a.com" || len(first.Broken) != 1 || first.Broken[0].URL != "https://a.com/gone" {
+		t.Errorf("unexpected first source: %+v", first)
+	}
+	if second.Page != "https://b.com" || len(second.Broken) != 2 || second.Broken[1].URL != "https://slow.com/logo.png" {
+		t.Errorf("unexpected second source: %+v", second)
+	}
+	if first.Broken[0].Sources != nil {
+		t.Errorf("expected grouped checks without sources, got %v", first.Broken[0].Sources)
+	}
+}
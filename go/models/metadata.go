@@ -0,0 +1,27 @@
+package models
+
+// Metadata holds the SEO-relevant metadata declared in the <head> of a page.
+// Values are recorded as written, except that the canonical and alternate URLs
+// are resolved to absolute URLs. For repeated tags the first occurrence wins.
+type Metadata struct {
+	Lang        string            `json:"lang,omitempty"`        // Language from <html lang>
+	Description string            `json:"description,omitempty"` // Content of <meta name="description">
+	Robots      string            `json:"robots,omitempty"`      // Content of <meta name="robots">
+	Canonical   string            `json:"canonical,omitempty"`   // Target of <link rel="canonical">
+	Hreflang    []AlternateLink   `json:"hreflang,omitempty"`    // <link rel="alternate" hreflang> translations
+	OpenGraph   map[string]string `json:"openGraph,omitempty"`   // Open Graph properties by name, e.g. "og:title"
+	Twitter     map[string]string `json:"twitter,omitempty"`     // Twitter card properties by name, e.g. "twitter:card"
+	JSONLD      []any             `json:"jsonLd,omitempty"`      // Parsed <script type="application/ld+json"> blocks
+}
+
+// AlternateLink is a translation of a page declared with an hreflang link.
+type AlternateLink struct {
+	Hreflang string `json:"hreflang"` // Language (and optional region) code, or "x-default"
+	URL      string `json:"url"`      // Absolute URL of the translation
+}
+
+// IsEmpty reports whether no metadata was found.
+func (m *Metadata) IsEmpty() bool {
+	return m == nil || (m.Lang == "" && m.Description == "" && m.Robots == "" && m.Canonical == "" &&
+		len(m.Hreflang) == 0 && len(m.OpenGraph) == 0 && len(m.Twitter) == 0 && len(m.JSONLD) == 0)
+}
@@ -9,12 +9,24 @@ import (
 // error information if the operation failed. A Page is always returned even
 // on failure to maintain consistent result handling.
 type Page struct {
-	URL       string    `json:"url"`             // The original URL that was scraped
-	Title     string    `json:"title"`           // The page title extracted from <title> tag
-	Links     []string  `json:"links"`           // All href attributes from <a> elements
-	Images    []string  `json:"images"`          // All src attributes from <img> elements
-	TimeStamp time.Time `json:"timestamp"`       // When the scraping operation started
-	Error     string    `json:"error,omitempty"` // Error message if scraping failed (empty on success)
+	URL        string         `json:"url"`                  // The original URL that was scraped
+	Title      string         `json:"title"`                // The page title extracted from <title> tag
+	Links      []string       `json:"links"`                // Absolute http(s) URLs from <a href> attributes (deduplicated)
+	Images     []string       `json:"images"`               // Absolute http(s) URLs from <img src> attributes (deduplicated)
+	OtherLinks []string       `json:"otherLinks,omitempty"` // Links with non-HTTP schemes (mailto:, tel:, javascript:, ...)
+	Fields     map[string]any `json:"fields,omitempty"`     // Custom fields extracted by the configured rules (string or list of strings)
+	Metadata   *Metadata      `json:"metadata,omitempty"`   // Description, canonical URL, Open Graph and other <head> metadata
+	Content    *Content       `json:"content,omitempty"`    // Main-content statistics, heading outline and (optionally) text
+	TimeStamp  time.Time      `json:"timestamp"`            // When the scraping operation started
+	Error      string         `json:"error,omitempty"`      // Error message if scraping failed (empty on success)
+	ErrorKind  ErrorKind      `json:"errorKind,omitempty"`  // Classified failure category (empty on success)
+	HTTPStatus int            `json:"httpStatus,omitempty"` // HTTP status code of a failed response
+	Depth      int            `json:"depth,omitempty"`      // Link distance from the seed URL (crawl mode only)
+	ParentURL  string         `json:"parentUrl,omitempty"`  // URL of the page that linked here (crawl mode only)
+	Tags       []string       `json:"tags,omitempty"`       // Tags of the URL list entry, for grouping results
+	Attempts   int            `json:"attempts,omitempty"`   // Number of HTTP requests made, including retries
+	Response   *ResponseInfo  `json:"response,omitempty"`   // HTTP response metadata (status, headers, timings, ...)
+	FromCache  bool           `json:"fromCache,omitempty"`  // Whether the body came from the response cache instead of a download
 }
 
 // HasError reports whether the page scraping encountered an error.
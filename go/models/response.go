@@ -0,0 +1,24 @@
+package models
+
+// ResponseInfo describes the HTTP response a page was scraped from.
+// It is recorded for successful pages and for HTTP error responses,
+// which makes the results usable for basic site health monitoring.
+type ResponseInfo struct {
+	StatusCode    int               `json:"statusCode"`              // HTTP status code of the final response
+	FinalURL      string            `json:"finalUrl"`                // URL after following redirects
+	RedirectChain []string          `json:"redirectChain,omitempty"` // URLs that redirected to the final URL, in order
+	ContentType   string            `json:"contentType,omitempty"`   // Value of the Content-Type header
+	ContentLength int64             `json:"contentLength"`           // Size of the received body in bytes
+	Headers       map[string]string `json:"headers,omitempty"`       // Selected response headers
+	Timing        Timing            `json:"timing"`                  // Request phase durations
+}
+
+// Timing holds the durations of the request phases in milliseconds.
+// Phases that did not happen (e.g. DNS and connect on a reused connection) are zero.
+type Timing struct {
+	DNSMs       float64 `json:"dnsMs"`       // DNS lookup
+	ConnectMs   float64 `json:"connectMs"`   // TCP connection establishment
+	TLSMs       float64 `json:"tlsMs"`       // TLS handshake
+	FirstByteMs float64 `json:"firstByteMs"` // Time from request start to the first response byte
+	TotalMs     float64 `json:"totalMs"`     // Time from request start until the body was read
+}
@@ -0,0 +1,87 @@
+package models
+
+import "sort"
+
+// Summary aggregates the outcome of a scraping run.
+// It is built incrementally with Add, so it can be computed while pages are
+// streamed to disk instead of from a complete slice held in memory.
+type Summary struct {
+	Total       int               `json:"total"`                 // Number of pages processed
+	Successful  int               `json:"successful"`            // Number of pages scraped without error
+	ErrorKinds  map[ErrorKind]int `json:"errorKinds,omitempty"`  // Failure count per error kind
+	Tags        map[string]*Tally `json:"tags,omitempty"`        // Page counts per tag
+	FromCache   int               `json:"fromCache,omitempty"`   // Number of pages served from the response cache
+	Interrupted bool              `json:"interrupted,omitempty"` // The run was stopped before all URLs were processed
+}
+
+// Tally counts processed and successful pages of a group, e.g. of a tag.
+type Tally struct {
+	Total      int `json:"total"`      // Number of pages processed
+	Successful int `json:"successful"` // Number of pages scraped without error
+}
+
+// Add records a single page in the summary. Failures without a kind count as ErrorKindUnknown.
+func (s *Summary) Add(page *Page) {
+	if page == nil {
+		return
+	}
+
+	s.Total++
+	if page.FromCache {
+		s.FromCache++
+	}
+	for _, tag := range page.Tags {
+		if s.Tags == nil {
+			s.Tags = make(map[string]*Tally)
+		}
+		tally, ok := s.Tags[tag]
+		if !ok {
+			tally = &Tally{}
+			s.Tags[tag] = tally
+		}
+		tally.Total++
+		if page.Success() {
+			tally.Successful++
+		}
+	}
+
+	if page.Success() {
+		s.Successful++
+		return
+	}
+
+	kind := page.ErrorKind
+	if kind == "" {
+		kind = ErrorKindUnknown
+	}
+	if s.ErrorKinds == nil {
+		s.ErrorKinds = make(map[ErrorKind]int)
+	}
+	s.ErrorKinds[kind]++
+}
+
+// SortedErrorKinds returns the recorded error kinds, most frequent first
+// (ties in alphabetical order).
+func (s *Summary) SortedErrorKinds() []ErrorKind {
+	kinds := make([]ErrorKind, 0, len(s.ErrorKinds))
+	for kind := range s.ErrorKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if s.ErrorKinds[kinds[i]] != s.ErrorKinds[kinds[j]] {
+			return s.ErrorKinds[kinds[i]] > s.ErrorKinds[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+	return kinds
+}
+
+// SortedTags returns the recorded tags in alphabetical order.
+func (s *Summary) SortedTags() []string {
+	tags := make([]string, 0, len(s.Tags))
+	for tag := range s.Tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,64 @@
+package models_test
+
+import (
+	"go-scraper/models"
+	"testing"
+)
+
+func TestSummary_Add(t *testing.T) {
+	var summary models.Summary
+	pages := []*models.Page{
+		{URL: "a", FromCache: true},
+		{URL: "b", Error: "boom", ErrorKind: models.ErrorKindTimeout},
+		{URL: "c", Error: "boom", ErrorKind: models.ErrorKindTimeout},
+		{URL: "d", Error: "boom"},
+		nil,
+	}
+	for _, page := range pages {
+		summary.Add(page)
+	}
+
+	if summary.Total != 4 || summary.Successful != 1 {
+		t.Errorf("expected 1/4 successful, got %d/%d", summary.Successful, summary.Total)
+	}
+	if summary.ErrorKinds[models.ErrorKindTimeout] != 2 || summary.ErrorKinds[models.ErrorKindUnknown] != 1 {
+		t.Errorf("unexpected error kinds: %v", summary.ErrorKinds)
+	}
+	if summary.FromCache != 1 {
+		t.Errorf("expected 1 page from cache, got %d", summary.FromCache)
+	}
+}
+
+func TestSummary_SortedErrorKinds(t *testing.T) {
+	summary := models.Summary{ErrorKinds: map[models.ErrorKind]int{
+		models.ErrorKindTimeout:    1,
+		models.ErrorKindHTTPStatus: 3,
+		models.ErrorKindDNS:        1,
+	}}
+
+	kinds := summary.SortedErrorKinds()
+	expected := []models.ErrorKind{models.ErrorKindHTTPStatus, models.ErrorKindDNS, models.ErrorKindTimeout}
+	if len(kinds) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, kinds)
+	}
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, kinds)
+			break
+		}
+	}
+}
+
+func TestSummary_Tags(t *testing.T) {
+	var summary models.Summary
+	summary.Add(&models.Page{URL: "a", Tags: []string{"news", "de"}})
+	summary.Add(&models.Page{URL: "b", Tags: []string{"news"}, Error: "boom"})
+	summary.Add(&models.Page{URL: "c"})
+
+	if news := summary.Tags["news"]; news == nil || news.Total != 2 || news.Successful != 1 {
+		t.Errorf("unexpected tally for news: %+v", news)
+	}
+	if tags := summary.SortedTags(); len(tags) != 2 || tags[0] != "de" || tags[1] != "news" {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+}
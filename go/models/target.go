@@ -0,0 +1,75 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// Target is a URL to scrape together with its per-URL options.
+// In a URL list a target is either a plain URL string or an object:
+//
+//	{"url": "https://example.com", "method": "GET", "headers": {"Accept-Language": "de"},
+//	 "tags": ["news"], "timeoutSeconds": 5, "extract": {"price": ".price"}}
+//
+// Options that are not set fall back to the global configuration.
+type Target struct {
+	URL            string                 `json:"url"`                      // The URL to scrape
+	Method         string                 `json:"method,omitempty"`         // HTTP method (default GET)
+	Headers        map[string]string      `json:"headers,omitempty"`        // Additional request headers; may override User-Agent
+	Tags           []string               `json:"tags,omitempty"`           // Labels copied to the Page for grouping results
+	TimeoutSeconds int                    `json:"timeoutSeconds,omitempty"` // Per-URL deadline overriding the configured one
+	Extract        map[string]ExtractRule `json:"extract,omitempty"`        // Custom fields to extract, by field name (added to the configured ones)
+}
+
+// NewTargets returns plain targets without options for the given URLs.
+func NewTargets(urls ...string) []Target {
+	targets := make([]Target, len(urls))
+	for i, url := range urls {
+		targets[i] = Target{URL: url}
+	}
+	return targets
+}
+
+// TargetURLs returns the URLs of the targets in order.
+func TargetURLs(targets []Target) []string {
+	urls := make([]string, len(targets))
+	for i, target := range targets {
+		urls[i] = target.URL
+	}
+	return urls
+}
+
+// HasOptions reports whether any option besides the URL is set.
+func (t Target) HasOptions() bool {
+	return t.Method != "" || len(t.Headers) > 0 || len(t.Tags) > 0 || t.TimeoutSeconds != 0 || len(t.Extract) > 0
+}
+
+// UnmarshalJSON accepts either a URL string or a target object.
+func (t *Target) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		*t = Target{}
+		return json.Unmarshal(trimmed, &t.URL)
+	}
+
+	type plain Target // without methods, to avoid recursion
+	var target plain
+	if err := json.Unmarshal(data, &target); err != nil {
+		return err
+	}
+	if target.URL == "" {
+		return errors.New(`target object without "url"`)
+	}
+	*t = Target(target)
+	return nil
+}
+
+// MarshalJSON writes targets without options as plain URL strings, so URL lists
+// keep their compact form when they are rewritten.
+func (t Target) MarshalJSON() ([]byte, error) {
+	if !t.HasOptions() {
+		return json.Marshal(t.URL)
+	}
+	type plain Target
+	return json.Marshal(plain(t))
+}
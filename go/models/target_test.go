@@ -0,0 +1,51 @@
+package models_test
+
+import (
+	"encoding/json"
+	"go-scraper/models"
+	"reflect"
+	"testing"
+)
+
+func TestTarget_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected models.Target
+		wantErr  bool
+	}{
+		{"String", `"https://a.com"`, models.Target{URL: "https://a.com"}, false},
+		{"Object", `{"url": "https://a.com", "method": "HEAD", "tags": ["news"]}`,
+			models.Target{URL: "https://a.com", Method: "HEAD", Tags: []string{"news"}}, false},
+		{"ObjectWithoutURL", `{"tags": ["news"]}`, models.Target{}, true},
+		{"Number", `42`, models.Target{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target models.Target
+			err := json.Unmarshal([]byte(tt.input), &target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(target, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, target)
+			}
+		})
+	}
+}
+
+func TestTarget_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal([]models.Target{
+		{URL: "https://a.com"},
+		{URL: "https://b.com", TimeoutSeconds: 5},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `["https://a.com",{"url":"https://b.com","timeoutSeconds":5}]`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
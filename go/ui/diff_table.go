@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"go-scraper/models"
+)
+
+// DiffItemsShown limits how many added or removed links and images are listed per
+// table cell; the rest are summarized as "... and N more".
+const DiffItemsShown = 5
+
+// RenderDiff writes the changes between two runs as a table with one row per
+// added, removed or changed page, followed by a one-line summary.
+func RenderDiff(w io.Writer, diff *models.ResultsDiff) {
+	_, _ = fmt.Fprintf(w, "Comparing %s → %s\n", diff.Old, diff.New)
+	if !diff.HasChanges() {
+		_, _ = fmt.Fprintln(w, "No changes.")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(table.StyleLight)
+	t.AppendHeader(table.Row{"URL", "Change", "Details"})
+
+	for _, url := range diff.Added {
+		t.AppendRow(table.Row{url, "added", ""})
+	}
+	for _, url := range diff.Removed {
+		t.AppendRow(table.Row{url, "removed", ""})
+	}
+	for _, change := range diff.Changed {
+		if change.Status != nil {
+			t.AppendRow(table.Row{change.URL, "status", fmt.Sprintf("%s → %s", change.Status.Old, change.Status.New)})
+		}
+		if change.Title != nil {
+			t.AppendRow(table.Row{change.URL, "title", fmt.Sprintf("%q → %q", change.Title.Old, change.Title.New)})
+		}
+		appendListRow(t, change.URL, "links", change.LinksAdded, change.LinksRemoved)
+		appendListRow(t, change.URL, "images", change.ImagesAdded, change.ImagesRemoved)
+		names := make([]string, 0, len(change.Fields))
+		for name := range change.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			field := change.Fields[name]
+			t.AppendRow(table.Row{change.URL, "field " + name, fmt.Sprintf("%q → %q", field.Old, field.New)})
+		}
+		if change.Metadata {
+			t.AppendRow(table.Row{change.URL, "metadata", ""})
+		}
+		if change.Content {
+			t.AppendRow(table.Row{change.URL, "content", ""})
+		}
+	}
+	t.Render()
+
+	_, _ = fmt.Fprintf(w, "%d added | %d removed | %d changed\n", len(diff.Added), len(diff.Removed), len(diff.Changed))
+}
+
+// appendListRow adds a row listing added (+) and removed (-) entries, if there are any.
+func appendListRow(t table.Writer, url, kind string, added, removed []string) {
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	lines := append(listLines("+", added), listLines("-", removed)...)
+	t.AppendRow(table.Row{url, fmt.Sprintf("%s +%d -%d", kind, len(added), len(removed)), strings.Join(lines, "\n")})
+}
+
+// listLines prefixes up to DiffItemsShown items and summarizes the rest.
+func listLines(prefix string, items []string) []string {
+	var lines []string
+	for i, item := range items {
+		if i == DiffItemsShown {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(items)-DiffItemsShown))
+			break
+		}
+		lines = append(lines, prefix+" "+item)
+	}
+	return lines
+}
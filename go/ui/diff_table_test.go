@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-scraper/models"
+)
+
+func TestRenderDiff(t *testing.T) {
+	var links []string
+	for i := 0; i < DiffItemsShown+2; i++ {
+		links = append(links, fmt.Sprintf("https://a.com/%d", i))
+	}
+	diff := &models.ResultsDiff{
+		Old:     "old.json",
+		New:     "new.json",
+		Added:   []string{"https://new.com"},
+		Removed: []string{},
+		Changed: []models.PageChange{{
+			URL:        "https://a.com",
+			Status:     &models.ValueChange{Old: "success", New: "timeout"},
+			LinksAdded: links,
+			Fields:     map[string]*models.ValueChange{"price": {Old: "10", New: "12"}},
+			Content:    true,
+		}},
+	}
+
+	var out bytes.Buffer
+	RenderDiff(&out, diff)
+
+	for _, want := range []string{
+		"Comparing old.json → new.json",
+		"https://new.com", "added",
+		"success → timeout",
+		"links +7 -0", "+ https://a.com/4", "... and 2 more",
+		"field price", `"10" → "12"`, "content",
+		"1 added | 0 removed | 1 changed",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "https://a.com/5") {
+		t.Errorf("expected links beyond %d to be summarized, got:\n%s", DiffItemsShown, out.String())
+	}
+}
+
+func TestRenderDiff_NoChanges(t *testing.T) {
+	var out bytes.Buffer
+	RenderDiff(&out, &models.ResultsDiff{Old: "a.json", New: "b.json"})
+	if !strings.Contains(out.String(), "No changes.") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
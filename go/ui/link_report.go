@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"go-scraper/models"
+)
+
+// RenderLinkReport writes the broken targets of a link check as a table grouped by
+// the pages referencing them, followed by a one-line summary.
+func RenderLinkReport(w io.Writer, report *models.LinkReport) {
+	if len(report.Sources) > 0 {
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.SetStyle(table.StyleLight)
+		t.AppendHeader(table.Row{"Page", "Broken target", "Type", "Status"})
+		t.SetColumnConfigs([]table.ColumnConfig{{Number: 1, AutoMerge: true}})
+
+		for _, source := range report.Sources {
+			for _, check := range source.Broken {
+				t.AppendRow(table.Row{source.Page, check.URL, string(check.Type), linkStatus(check)})
+			}
+			t.AppendSeparator()
+		}
+		t.Render()
+	} else {
+		_, _ = fmt.Fprintln(w, "No broken links.")
+	}
+
+	_, _ = fmt.Fprintf(w, "%d checked | %d broken | %d skipped\n", report.Checked, report.Broken, report.Skipped)
+}
+
+// linkStatus describes why a target is broken: its HTTP status code and the method
+// that received it, or the error kind if there was no response.
+func linkStatus(check models.LinkCheck) string {
+	if check.StatusCode != 0 {
+		return strconv.Itoa(check.StatusCode) + " (" + check.Method + ")"
+	}
+	return string(check.ErrorKind)
+}
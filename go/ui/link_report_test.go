@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go-scraper/models"
+)
+
+func TestRenderLinkReport(t *testing.T) {
+	report := &models.LinkReport{
+		Checked: 10,
+		Broken:  2,
+		Skipped: 1,
+		Sources: []models.SourceLinks{{
+			Page: "https://a.com",
+			Broken: []models.LinkCheck{
+				{LinkTarget: models.LinkTarget{URL: "https://a.com/gone", Type: models.LinkTypeLink}, Method: "GET", StatusCode: 404, ErrorKind: models.ErrorKindHTTPStatus},
+				{LinkTarget: models.LinkTarget{URL: "https://slow.com/logo.png", Type: models.LinkTypeImage}, Method: "HEAD", ErrorKind: models.ErrorKindTimeout},
+			},
+		}},
+	}
+
+	var out bytes.Buffer
+	RenderLinkReport(&out, report)
+
+	for _, want := range []string{
+		"https://a.com", "https://a.com/gone", "404 (GET)",
+		"https://slow.com/logo.png", "image", "timeout",
+		"10 checked | 2 broken | 1 skipped",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRenderLinkReport_NoBrokenLinks(t *testing.T) {
+	var out bytes.Buffer
+	RenderLinkReport(&out, &models.LinkReport{Checked: 3})
+	if !strings.Contains(out.String(), "No broken links.") || !strings.Contains(out.String(), "3 checked | 0 broken") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
@@ -1,7 +1,12 @@
 package ui
 
+import (
+	"strconv"
+	"strings"
+)
+
 // ScrapeMode represents the execution mode for the web scraper.
-// Users can choose between sequential and parallel execution.
+// Users can choose between sequential, parallel, crawl and link-check execution.
 type ScrapeMode int
 
 const (
@@ -12,8 +17,21 @@ const (
 	// ModeParallel indicates URLs should be scraped concurrently using a worker pool.
 	// This mode is faster for multiple URLs but uses more system resources.
 	ModeParallel ScrapeMode = 2
+
+	// ModeCrawl indicates the seed URLs should be scraped and the links discovered
+	// on them followed recursively, up to the configured depth and page limits.
+	ModeCrawl ScrapeMode = 3
+
+	// ModeLinkCheck indicates the seed URLs should be scraped in parallel and every
+	// link and image found on them checked for reachability afterwards.
+	ModeLinkCheck ScrapeMode = 4
 )
 
+// Modes returns all valid scrape modes in menu order.
+func Modes() []ScrapeMode {
+	return []ScrapeMode{ModeSequential, ModeParallel, ModeCrawl, ModeLinkCheck}
+}
+
 // String returns a human-readable string representation of the ScrapeMode.
 // This is useful for logging, debugging, and user-facing messages.
 func (m ScrapeMode) String() string {
@@ -22,15 +40,19 @@ func (m ScrapeMode) String() string {
 		return "Sequential"
 	case ModeParallel:
 		return "Parallel"
+	case ModeCrawl:
+		return "Crawl"
+	case ModeLinkCheck:
+		return "Link check"
 	default:
 		return "Unknown"
 	}
 }
 
 // IsValid reports whether the ScrapeMode is a valid mode value.
-// Returns true only for ModeSequential and ModeParallel.
+// Returns true only for ModeSequential, ModeParallel, ModeCrawl and ModeLinkCheck.
 func (m ScrapeMode) IsValid() bool {
-	return m == ModeSequential || m == ModeParallel
+	return m == ModeSequential || m == ModeParallel || m == ModeCrawl || m == ModeLinkCheck
 }
 
 // ParseScrapeMode converts an integer input to a ScrapeMode.
@@ -39,3 +61,27 @@ func ParseScrapeMode(input int) (ScrapeMode, bool) {
 	mode := ScrapeMode(input)
 	return mode, mode.IsValid()
 }
+
+// ParseScrapeModeName converts a mode name such as "sequential" or "link-check"
+// (case-insensitive; spaces, hyphens and underscores are ignored) to a ScrapeMode.
+// The numeric menu values ("1" to "4") are accepted as well so that flags and
+// interactive input behave the same.
+// Returns the mode and true if valid, or zero value and false if invalid.
+func ParseScrapeModeName(input string) (ScrapeMode, bool) {
+	normalized := normalizeModeName(input)
+	for _, mode := range Modes() {
+		if normalized == normalizeModeName(mode.String()) {
+			return mode, true
+		}
+	}
+	if parsed, err := strconv.Atoi(normalized); err == nil {
+		return ParseScrapeMode(parsed)
+	}
+	return ScrapeMode(0), false
+}
+
+// normalizeModeName lowercases a mode name and removes separators, so "Link check",
+// "link-check" and "linkcheck" compare equal.
+func normalizeModeName(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
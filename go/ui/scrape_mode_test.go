@@ -9,6 +9,8 @@ func TestScrapeMode_String(t *testing.T) {
 	}{
 		{ModeSequential, "Sequential"},
 		{ModeParallel, "Parallel"},
+		{ModeCrawl, "Crawl"},
+		{ModeLinkCheck, "Link check"},
 		{ScrapeMode(99), "Unknown"},
 		{ScrapeMode(0), "Unknown"},
 		{ScrapeMode(-1), "Unknown"},
@@ -30,8 +32,10 @@ func TestScrapeMode_IsValid(t *testing.T) {
 	}{
 		{ModeSequential, true},
 		{ModeParallel, true},
+		{ModeCrawl, true},
+		{ModeLinkCheck, true},
 		{ScrapeMode(0), false},
-		{ScrapeMode(3), false},
+		{ScrapeMode(5), false},
 		{ScrapeMode(99), false},
 		{ScrapeMode(-1), false},
 	}
@@ -53,8 +57,10 @@ func TestParseScrapeMode(t *testing.T) {
 	}{
 		{1, true, ModeSequential},
 		{2, true, ModeParallel},
+		{3, true, ModeCrawl},
+		{4, true, ModeLinkCheck},
 		{0, false, ScrapeMode(0)},
-		{3, false, ScrapeMode(3)},
+		{5, false, ScrapeMode(5)},
 		{-1, false, ScrapeMode(-1)},
 		{99, false, ScrapeMode(99)},
 	}
@@ -71,3 +77,37 @@ func TestParseScrapeMode(t *testing.T) {
 		})
 	}
 }
+
+func TestParseScrapeModeName(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectedOk  bool
+		expectedVal ScrapeMode
+	}{
+		{"sequential", true, ModeSequential},
+		{"Parallel", true, ModeParallel},
+		{" PARALLEL ", true, ModeParallel},
+		{"1", true, ModeSequential},
+		{"2", true, ModeParallel},
+		{"crawl", true, ModeCrawl},
+		{"link-check", true, ModeLinkCheck},
+		{"Link check", true, ModeLinkCheck},
+		{"linkcheck", true, ModeLinkCheck},
+		{"4", true, ModeLinkCheck},
+		{"", false, ScrapeMode(0)},
+		{"fast", false, ScrapeMode(0)},
+		{"5", false, ScrapeMode(5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			mode, ok := ParseScrapeModeName(tt.input)
+			if ok != tt.expectedOk {
+				t.Errorf("ParseScrapeModeName(%q) ok = %v, want %v", tt.input, ok, tt.expectedOk)
+			}
+			if mode != tt.expectedVal {
+				t.Errorf("ParseScrapeModeName(%q) mode = %v, want %v", tt.input, mode, tt.expectedVal)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// IsInteractive reports whether stdin is attached to a terminal.
+// Interactive prompts are only shown when this returns true, so the scraper
+// can run unattended from cron jobs, CI pipelines or shell pipes.
+// Character devices such as /dev/null are not treated as terminals.
+func IsInteractive() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
@@ -0,0 +1,213 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-scraper/models"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// CheckpointFile is the name of the checkpoint file inside the results directory
+	CheckpointFile = "checkpoint.json"
+	// checkpointSaveInterval limits how often the checkpoint is rewritten while pages complete
+	checkpointSaveInterval = time.Second
+)
+
+// URLStatus is the processing state of a URL recorded in a checkpoint.
+type URLStatus string
+
+const (
+	// URLStatusPending marks URLs that have not been scraped yet (or were cancelled).
+	URLStatusPending URLStatus = "pending"
+	// URLStatusDone marks URLs that were scraped successfully.
+	URLStatusDone URLStatus = "done"
+	// URLStatusFailed marks URLs whose scrape failed; they are retried on resume.
+	URLStatusFailed URLStatus = "failed"
+)
+
+// Checkpoint records the progress of a run so it can be resumed after an interruption.
+// The pages themselves live in the NDJSON result streams listed in Streams; the
+// checkpoint only tracks which URLs are done, failed or still pending.
+type Checkpoint struct {
+	URLsFile  string               `json:"urlsFile"`  // URL list the run was started from
+	Streams   []string             `json:"streams"`   // NDJSON result streams of all runs, oldest first
+	UpdatedAt time.Time            `json:"updatedAt"` // Last time the checkpoint was written
+	URLs      map[string]URLStatus `json:"urls"`      // Status per URL
+}
+
+// CheckpointPath returns the location of the checkpoint file in the results directory.
+func CheckpointPath(folder string) string {
+	return filepath.Join(folder, CheckpointFile)
+}
+
+// NewCheckpoint creates a checkpoint with every URL pending.
+func NewCheckpoint(urlsFile string, urls []string) *Checkpoint {
+	cp := &Checkpoint{URLsFile: urlsFile, URLs: make(map[string]URLStatus, len(urls))}
+	for _, u := range urls {
+		cp.URLs[u] = URLStatusPending
+	}
+	return cp
+}
+
+// LoadCheckpoint reads a checkpoint and replays its result streams, so pages that
+// completed after the last checkpoint write (e.g. right before a crash) count as well.
+// Streams that no longer exist are dropped from the checkpoint.
+func LoadCheckpoint(fs FileSystem, path string) (*Checkpoint, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	if cp.URLs == nil {
+		cp.URLs = make(map[string]URLStatus)
+	}
+
+	streams := cp.Streams[:0]
+	for _, stream := range cp.Streams {
+		if _, err := fs.Stat(stream); err != nil {
+			continue
+		}
+		if err := ReadResultsStream(fs, stream, func(page *models.Page) error {
+			cp.Record(page)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		streams = append(streams, stream)
+	}
+	cp.Streams = streams
+
+	return &cp, nil
+}
+
+// Save writes the checkpoint as JSON. The file is written next to the checkpoint and
+// then renamed over it, so a crash during the write leaves the previous checkpoint intact.
+func (c *Checkpoint) Save(fs FileSystem, path string) error {
+	c.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize checkpoint: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := fs.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %w", tmp, err)
+	}
+	if err := fs.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint %s: %w", path, err)
+	}
+	return nil
+}
+
+// Record updates the status of a page's URL. Successful pages are done, cancelled
+// pages stay pending and all other failures are marked failed.
+func (c *Checkpoint) Record(page *models.Page) {
+	if page == nil {
+		return
+	}
+	switch {
+	case page.Success():
+		c.URLs[page.URL] = URLStatusDone
+	case page.ErrorKind == models.ErrorKindCancelled:
+		if c.URLs[page.URL] != URLStatusDone {
+			c.URLs[page.URL] = URLStatusPending
+		}
+	default:
+		c.URLs[page.URL] = URLStatusFailed
+	}
+}
+
+// RemainingTargets returns the targets whose URLs are not done yet, in the given order.
+// URLs unknown to the checkpoint (e.g. added to the URL list since) are included.
+func (c *Checkpoint) RemainingTargets(targets []models.Target) []models.Target {
+	remaining := make([]models.Target, 0, len(targets))
+	for _, target := range targets {
+		if c.URLs[target.URL] != URLStatusDone {
+			remaining = append(remaining, target)
+		}
+	}
+	return remaining
+}
+
+// CheckURLList verifies that the checkpoint was recorded for the URL list in urlsFile
+// and returns how many of urls were added to the list since, and how many of the
+// checkpoint's URLs were removed from it.
+func (c *Checkpoint) CheckURLList(urlsFile string, urls []string) (added, removed int, err error) {
+	if filepath.Clean(c.URLsFile) != filepath.Clean(urlsFile) {
+		return 0, 0, fmt.Errorf("checkpoint was recorded for %s, not %s", c.URLsFile, urlsFile)
+	}
+	listed := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		if _, ok := c.URLs[u]; !ok && !listed[u] {
+			added++
+		}
+		listed[u] = true
+	}
+	for u := range c.URLs {
+		if !listed[u] {
+			removed++
+		}
+	}
+	return added, removed, nil
+}
+
+// Counts returns the number of URLs per status.
+func (c *Checkpoint) Counts() map[URLStatus]int {
+	counts := make(map[URLStatus]int, 3)
+	for _, status := range c.URLs {
+		counts[status]++
+	}
+	return counts
+}
+
+// CheckpointWriter keeps a checkpoint up to date while pages complete.
+// It implements core.ResultSink. To limit disk writes the checkpoint is rewritten
+// at most once per second; Close writes the final state.
+type CheckpointWriter struct {
+	fs         FileSystem
+	path       string
+	checkpoint *Checkpoint
+	lastSave   time.Time
+	err        error
+}
+
+// NewCheckpointWriter writes the initial checkpoint state to path and returns the writer.
+func NewCheckpointWriter(fs FileSystem, path string, checkpoint *Checkpoint) (*CheckpointWriter, error) {
+	w := &CheckpointWriter{fs: fs, path: path, checkpoint: checkpoint}
+	if err := w.save(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write records the page and persists the checkpoint if the save interval has elapsed.
+func (w *CheckpointWriter) Write(page *models.Page) error {
+	w.checkpoint.Record(page)
+	if time.Since(w.lastSave) < checkpointSaveInterval {
+		return nil
+	}
+	return w.save()
+}
+
+// Close writes the final checkpoint state and returns the first error that occurred.
+func (w *CheckpointWriter) Close() error {
+	if err := w.save(); err != nil {
+		return err
+	}
+	return w.err
+}
+
+// save writes the checkpoint and remembers the first failure.
+func (w *CheckpointWriter) save() error {
+	w.lastSave = time.Now()
+	err := w.checkpoint.Save(w.fs, w.path)
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+package util_test
+
+import (
+	"go-scraper/models"
+	"go-scraper/util"
+	"testing"
+)
+
+func TestCheckpoint_RecordAndRemaining(t *testing.T) {
+	urls := []string{"a", "b", "c", "d"}
+	cp := util.NewCheckpoint("urls.json", urls)
+
+	cp.Record(&models.Page{URL: "a"})
+	cp.Record(&models.Page{URL: "b", Error: "boom", ErrorKind: models.ErrorKindTimeout})
+	cp.Record(&models.Page{URL: "c", Error: "skipped", ErrorKind: models.ErrorKindCancelled})
+
+	expected := map[string]util.URLStatus{
+		"a": util.URLStatusDone,
+		"b": util.URLStatusFailed,
+		"c": util.URLStatusPending,
+		"d": util.URLStatusPending,
+	}
+	for url, status := range expected {
+		if cp.URLs[url] != status {
+			t.Errorf("%s: expected %s, got %s", url, status, cp.URLs[url])
+		}
+	}
+
+	// Failed, pending and newly added URLs are scraped again
+	var targets []models.Target
+	for _, url := range append(urls, "e") {
+		targets = append(targets, models.Target{URL: url})
+	}
+	remaining := cp.RemainingTargets(targets)
+	if len(remaining) != 4 || remaining[0].URL != "b" || remaining[3].URL != "e" {
+		t.Errorf("unexpected remaining URLs: %v", remaining)
+	}
+}
+
+func TestLoadCheckpoint_ReplaysStreams(t *testing.T) {
+	fs := newMockFS()
+	stream, _ := util.CreateResultsStream(fs, fakeTimeProvider{}, "out")
+
+	cp := util.NewCheckpoint("urls.json", []string{"a", "b"})
+	cp.Streams = []string{stream.Path(), "out/missing.ndjson"}
+	if _, err := util.NewCheckpointWriter(fs, util.CheckpointPath("out"), cp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The page reaches the stream, but the process dies before the checkpoint is rewritten
+	_ = stream.Write(&models.Page{URL: "a"})
+	_ = stream.Close()
+
+	loaded, err := util.LoadCheckpoint(fs, util.CheckpointPath("out"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.URLs["a"] != util.URLStatusDone || loaded.URLs["b"] != util.URLStatusPending {
+		t.Errorf("unexpected statuses after replay: %v", loaded.URLs)
+	}
+	if len(loaded.Streams) != 1 || loaded.Streams[0] != stream.Path() {
+		t.Errorf("expected missing streams to be dropped, got %v", loaded.Streams)
+	}
+}
+
+func TestLoadCheckpoint_SkipsPartialLastPage(t *testing.T) {
+	fs := newMockFS()
+	cp := util.NewCheckpoint("urls.json", []string{"a", "b"})
+	cp.Streams = []string{"out/crashed.ndjson"}
+	if err := cp.Save(fs, util.CheckpointPath("out")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The process died while writing the page of b
+	fs.files["out/crashed.ndjson"] = []byte("{\"url\":\"a\"}\n{\"url\":\"b\",\"tit")
+
+	loaded, err := util.LoadCheckpoint(fs, util.CheckpointPath("out"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.URLs["a"] != util.URLStatusDone || loaded.URLs["b"] != util.URLStatusPending {
+		t.Errorf("unexpected statuses after replay: %v", loaded.URLs)
+	}
+}
+
+func TestCheckpoint_CheckURLList(t *testing.T) {
+	cp := util.NewCheckpoint("lists/urls.json", []string{"a", "b", "c"})
+
+	if _, _, err := cp.CheckURLList("other.json", []string{"a", "b", "c"}); err == nil {
+		t.Error("expected an error for another URL file")
+	}
+	added, removed, err := cp.CheckURLList("./lists/urls.json", []string{"a", "b", "c"})
+	if err != nil || added != 0 || removed != 0 {
+		t.Errorf("expected an unchanged list, got %d added, %d removed (%v)", added, removed, err)
+	}
+	added, removed, err = cp.CheckURLList("lists/urls.json", []string{"a", "d", "e", "d"})
+	if err != nil || added != 2 || removed != 2 {
+		t.Errorf("expected 2 added and 2 removed URLs, got %d added, %d removed (%v)", added, removed, err)
+	}
+}
+
+func TestCheckpointWriter_Close(t *testing.T) {
+	fs := newMockFS()
+	path := util.CheckpointPath("out")
+	writer, err := util.NewCheckpointWriter(fs, path, util.NewCheckpoint("urls.json", []string{"a"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_ = writer.Write(&models.Page{URL: "a"})
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := util.LoadCheckpoint(fs, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counts := loaded.Counts(); counts[util.URLStatusDone] != 1 || counts[util.URLStatusPending] != 0 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
+
+func TestCheckpoint_SaveReplacesFile(t *testing.T) {
+	fs := newMockFS()
+	path := util.CheckpointPath("out")
+	fs.files[path] = []byte(`{"urlsFile": "old.json"}`)
+
+	cp := util.NewCheckpoint("urls.json", []string{"a"})
+	if err := cp.Save(fs, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := fs.files[path+".tmp"]; ok {
+		t.Error("expected the temporary file to be renamed over the checkpoint")
+	}
+	loaded, err := util.LoadCheckpoint(fs, path)
+	if err != nil || loaded.URLs["a"] != util.URLStatusPending {
+		t.Errorf("expected the new checkpoint, got %+v (%v)", loaded, err)
+	}
+}
@@ -0,0 +1,266 @@
+package util
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"go-scraper/models"
+	"sort"
+	"strings"
+)
+
+// DefaultExportFormat is the export format used when none is configured.
+const DefaultExportFormat = "json"
+
+// PageSource iterates over scraped pages in order, calling fn for each page.
+// A source may be iterated more than once, which lets exporters compute a
+// summary before writing the individual pages. Iteration stops at the first
+// error returned by fn.
+type PageSource func(fn func(page *models.Page) error) error
+
+// SlicePages returns a PageSource over an in-memory slice. Nil pages are skipped.
+func SlicePages(pages []*models.Page) PageSource {
+	return func(fn func(page *models.Page) error) error {
+		for _, page := range pages {
+			if page == nil {
+				continue
+			}
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// StreamPages returns a PageSource that reads an NDJSON results stream from disk
+// one page at a time (see ReadResultsStream).
+func StreamPages(fs FileSystem, path string) PageSource {
+	return func(fn func(page *models.Page) error) error {
+		return ReadResultsStream(fs, path, fn)
+	}
+}
+
+// ConcatPages returns a PageSource that iterates the given sources one after another.
+func ConcatPages(sources ...PageSource) PageSource {
+	return func(fn func(page *models.Page) error) error {
+		for _, source := range sources {
+			if err := source(fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// LatestPages returns a PageSource that yields only the last page recorded for each URL,
+// e.g. to merge a resumed run with the pages of the interrupted one. The source is
+// iterated twice; only a counter per URL is kept in memory.
+func LatestPages(source PageSource) PageSource {
+	return func(fn func(page *models.Page) error) error {
+		remaining := make(map[string]int)
+		if err := source(func(page *models.Page) error {
+			remaining[page.URL]++
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		return source(func(page *models.Page) error {
+			remaining[page.URL]--
+			if remaining[page.URL] > 0 {
+				return nil // a later page for this URL follows
+			}
+			return fn(page)
+		})
+	}
+}
+
+// Exporter writes scrape results in a specific file format.
+// This abstraction allows adding output formats without changing the callers.
+//
+// Implementations should:
+//   - Write one or more files whose names start with basePath (the path without extension)
+//   - Process pages one at a time so large result sets need little memory
+//   - Return the paths of all files written
+type Exporter interface {
+	Format() string
+	Export(fs FileSystem, basePath string, pages PageSource) ([]string, error)
+}
+
+// exporters maps format names to their implementation.
+var exporters = map[string]Exporter{
+	"json":     JSONExporter{},
+	"ndjson":   NDJSONExporter{},
+	"csv":      CSVExporter{},
+	"xml":      XMLExporter{},
+	"markdown": MarkdownExporter{},
+	"html":     HTMLExporter{},
+}
+
+// ExporterFor returns the exporter registered for a format name (case-insensitive).
+func ExporterFor(format string) (Exporter, bool) {
+	exporter, ok := exporters[strings.ToLower(strings.TrimSpace(format))]
+	return exporter, ok
+}
+
+// ExportFormats returns the names of all supported export formats in sorted order.
+func ExportFormats() []string {
+	formats := make([]string, 0, len(exporters))
+	for format := range exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ExportResults writes the pages in every requested format and returns all files written.
+// basePath is the output path without extension (e.g. "output/scrape-results-1760563678815").
+// Unknown formats are reported as an error before any file is written.
+func ExportResults(fs FileSystem, basePath string, formats []string, pages PageSource) ([]string, error) {
+	selected := make([]Exporter, 0, len(formats))
+	for _, format := range formats {
+		exporter, ok := ExporterFor(format)
+		if !ok {
+			return nil, fmt.Errorf("unknown export format %q (supported: %s)", format, strings.Join(ExportFormats(), ", "))
+		}
+		selected = append(selected, exporter)
+	}
+
+	var paths []string
+	for _, exporter := range selected {
+		written, err := exporter.Export(fs, basePath, pages)
+		paths = append(paths, written...)
+		if err != nil {
+			return paths, fmt.Errorf("failed to export %s results: %w", exporter.Format(), err)
+		}
+	}
+	return paths, nil
+}
+
+// writeExportFile creates path and passes a buffered writer to write.
+// The buffer is flushed and the file closed even if write fails; the first error is returned.
+func writeExportFile(fs FileSystem, path string, write func(w *bufio.Writer) error) error {
+	file, err := fs.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	w := bufio.NewWriter(file)
+	err = write(w)
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// JSONExporter writes an indented JSON array of the pages, streamed page by page.
+type JSONExporter struct{}
+
+// Format returns "json".
+func (JSONExporter) Format() string { return "json" }
+
+// Export writes basePath + ".json".
+func (JSONExporter) Export(fs FileSystem, basePath string, pages PageSource) ([]string, error) {
+	path := basePath + ".json"
+	err := writeExportFile(fs, path, func(w *bufio.Writer) error {
+		count := 0
+		err := pages(func(page *models.Page) error {
+			data, err := json.MarshalIndent(page, "  ", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to serialize page %s: %w", page.URL, err)
+			}
+			separator := ",\n  "
+			if count == 0 {
+				separator = "[\n  "
+			}
+			count++
+			if _, err := w.WriteString(separator); err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			_, err = w.WriteString("[]")
+		} else {
+			_, err = w.WriteString("\n]")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+// NDJSONExporter writes one JSON object per line, convenient for tools like jq.
+type NDJSONExporter struct{}
+
+// Format returns "ndjson".
+func (NDJSONExporter) Format() string { return "ndjson" }
+
+// Export writes basePath + ".ndjson".
+func (NDJSONExporter) Export(fs FileSystem, basePath string, pages PageSource) ([]string, error) {
+	path := basePath + resultsStreamExt
+	err := writeExportFile(fs, path, func(w *bufio.Writer) error {
+		encoder := json.NewEncoder(w)
+		return pages(func(page *models.Page) error {
+			return encoder.Encode(page)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+// fieldNames returns the names of all custom fields found on the pages, sorted.
+func fieldNames(pages PageSource) ([]string, error) {
+	seen := make(map[string]struct{})
+	err := pages(func(page *models.Page) error {
+		for name := range page.Fields {
+			seen[name] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// fieldValues returns the values of a custom field as strings. Fields are strings or
+// lists of strings; lists read back from a results stream are []any.
+func fieldValues(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = fmt.Sprint(item)
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"bufio"
+	"encoding/csv"
+	"go-scraper/models"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvPageHeader lists the columns of the pages table.
+var csvPageHeader = []string{
+	"url", "title", "success", "error_kind", "error", "http_status", "depth",
+	"parent_url", "attempts", "links", "images", "timestamp", "tags",
+}
+
+const (
+	// csvListSeparator joins multi-valued cells such as tags.
+	csvListSeparator = ";"
+	// csvFieldPrefix starts the column names of custom fields.
+	csvFieldPrefix = "field:"
+)
+
+// csvLinkHeader lists the columns of the links table.
+var csvLinkHeader = []string{"page_url", "type", "url"}
+
+// CSVExporter writes two CSV tables: one row per page, and one row per
+// discovered link or image (linked to its page by page_url). Custom fields get one
+// "field:<name>" column each in the pages table.
+type CSVExporter struct{}
+
+// Format returns "csv".
+func (CSVExporter) Format() string { return "csv" }
+
+// Export writes basePath + ".csv" (pages) and basePath + "-links.csv" (links and images).
+func (CSVExporter) Export(fs FileSystem, basePath string, pages PageSource) ([]string, error) {
+	fields, err := fieldNames(pages)
+	if err != nil {
+		return nil, err
+	}
+
+	pagesPath := basePath + ".csv"
+	err = writeExportFile(fs, pagesPath, func(w *bufio.Writer) error {
+		cw := csv.NewWriter(w)
+		header := slices.Clone(csvPageHeader)
+		for _, name := range fields {
+			header = append(header, csvFieldPrefix+name)
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		err := pages(func(page *models.Page) error {
+			row := []string{
+				page.URL,
+				page.Title,
+				strconv.FormatBool(page.Success()),
+				string(page.ErrorKind),
+				page.Error,
+				formatOptionalInt(page.HTTPStatus),
+				strconv.Itoa(page.Depth),
+				page.ParentURL,
+				formatOptionalInt(page.Attempts),
+				strconv.Itoa(len(page.Links)),
+				strconv.Itoa(len(page.Images)),
+				page.TimeStamp.Format(time.RFC3339),
+				strings.Join(page.Tags, csvListSeparator),
+			}
+			for _, name := range fields {
+				row = append(row, strings.Join(fieldValues(page.Fields[name]), csvListSeparator))
+			}
+			return cw.Write(row)
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	linksPath := basePath + "-links.csv"
+	err = writeExportFile(fs, linksPath, func(w *bufio.Writer) error {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvLinkHeader); err != nil {
+			return err
+		}
+		err := pages(func(page *models.Page) error {
+			for _, group := range []struct {
+				kind string
+				urls []string
+			}{{"link", page.Links}, {"image", page.Images}, {"other", page.OtherLinks}} {
+				for _, u := range group.urls {
+					if err := cw.Write([]string{page.URL, group.kind, u}); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return []string{pagesPath}, err
+	}
+
+	return []string{pagesPath, linksPath}, nil
+}
+
+// formatOptionalInt renders zero as an empty cell.
+func formatOptionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
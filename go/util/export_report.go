@@ -0,0 +1,232 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"go-scraper/models"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// summarize computes the run summary from a page source.
+func summarize(pages PageSource) (*models.Summary, error) {
+	summary := &models.Summary{}
+	err := pages(func(page *models.Page) error {
+		summary.Add(page)
+		return nil
+	})
+	return summary, err
+}
+
+// pageStatus describes the outcome of a page for reports ("ok" or the error kind).
+func pageStatus(page *models.Page) string {
+	if page.Success() {
+		return "ok"
+	}
+	if page.ErrorKind != "" {
+		return string(page.ErrorKind)
+	}
+	return string(models.ErrorKindUnknown)
+}
+
+// MarkdownExporter writes a summary and a table of all pages, suitable for pull requests.
+type MarkdownExporter struct{}
+
+// Format returns "markdown".
+func (MarkdownExporter) Format() string { return "markdown" }
+
+// Export writes basePath + ".md".
+func (MarkdownExporter) Export(fs FileSystem, basePath string, pages PageSource) ([]string, error) {
+	summary, err := summarize(pages)
+	if err != nil {
+		return nil, err
+	}
+
+	path := basePath + ".md"
+	err = writeExportFile(fs, path, func(w *bufio.Writer) error {
+		fmt.Fprintf(w, "# Scrape Results\n\n")
+		fmt.Fprintf(w, "- **Pages:** %d\n", summary.Total)
+		fmt.Fprintf(w, "- **Successful:** %d\n", summary.Successful)
+		fmt.Fprintf(w, "- **Failed:** %d\n", summary.Total-summary.Successful)
+		for _, kind := range summary.SortedErrorKinds() {
+			fmt.Fprintf(w, "  - `%s`: %d\n", kind, summary.ErrorKinds[kind])
+		}
+
+		if len(summary.Tags) > 0 {
+			fmt.Fprintf(w, "\n| Tag | Pages | Successful |\n")
+			fmt.Fprintf(w, "|-----|------:|-----------:|\n")
+			for _, tag := range summary.SortedTags() {
+				fmt.Fprintf(w, "| %s | %d | %d |\n", markdownCell(tag), summary.Tags[tag].Total, summary.Tags[tag].Successful)
+			}
+		}
+
+		fmt.Fprintf(w, "\n| # | URL | Title | Status | Links | Images | Tags |\n")
+		fmt.Fprintf(w, "|--:|-----|-------|--------|------:|-------:|------|\n")
+		index := 0
+		err := pages(func(page *models.Page) error {
+			index++
+			_, err := fmt.Fprintf(w, "| %d | %s | %s | %s | %d | %d | %s |\n",
+				index, markdownCell(page.URL), markdownCell(page.Title), pageStatus(page),
+				len(page.Links), len(page.Images), markdownCell(strings.Join(page.Tags, ", ")))
+			return err
+		})
+		if err != nil || summary.Successful == summary.Total {
+			return err
+		}
+
+		fmt.Fprintf(w, "\n## Errors\n\n")
+		return pages(func(page *models.Page) error {
+			if page.Success() {
+				return nil
+			}
+			_, err := fmt.Fprintf(w, "- %s: %s\n", markdownCell(page.URL), markdownCell(page.Error))
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+// markdownCell escapes text for use inside a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// htmlReport holds the templates of the HTML report. The header and footer are
+// rendered once and the row template once per page, so pages are never collected.
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{"join": strings.Join}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Scrape Results</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; }
+th, td { border: 1px solid #ddd; padding: .4rem .6rem; text-align: left; vertical-align: top; }
+th { background: #f3f3f3; cursor: pointer; user-select: none; }
+th::after { content: " \2195"; color: #999; }
+td.num { text-align: right; }
+tr.failed td { background: #fff1f0; }
+.summary td:first-child { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Scrape Results</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}</p>
+<table class="summary sortable">
+<thead><tr><th>Metric</th><th>Count</th></tr></thead>
+<tbody>
+<tr><td>Pages</td><td class="num">{{.Summary.Total}}</td></tr>
+<tr><td>Successful</td><td class="num">{{.Summary.Successful}}</td></tr>
+{{range .ErrorKinds}}<tr><td>Failed: {{.Kind}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</tbody>
+</table>
+{{with .Tags}}<table class="sortable">
+<thead><tr><th>Tag</th><th>Pages</th><th>Successful</th></tr></thead>
+<tbody>
+{{range .}}<tr><td>{{.Tag}}</td><td class="num">{{.Total}}</td><td class="num">{{.Successful}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}<table class="sortable">
+<thead><tr><th>#</th><th>URL</th><th>Title</th><th>Status</th><th>HTTP</th><th>Links</th><th>Images</th><th>Tags</th><th>Error</th></tr></thead>
+<tbody>
+{{end}}
+{{define "row"}}<tr{{if not .Page.Success}} class="failed"{{end}}><td class="num">{{.Index}}</td><td><a href="{{.Page.URL}}">{{.Page.URL}}</a></td><td>{{.Page.Title}}</td><td>{{.Status}}</td><td class="num">{{with .Page.Response}}{{.StatusCode}}{{else}}{{with .Page.HTTPStatus}}{{.}}{{end}}{{end}}</td><td class="num">{{len .Page.Links}}</td><td class="num">{{len .Page.Images}}</td><td>{{join .Page.Tags ", "}}</td><td>{{.Page.Error}}</td></tr>
+{{end}}
+{{define "footer"}}</tbody>
+</table>
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, column) {
+    var ascending = true;
+    th.addEventListener("click", function () {
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[column].textContent.trim(), y = b.cells[column].textContent.trim();
+        var nx = parseFloat(x), ny = parseFloat(y);
+        var order = (!isNaN(nx) && !isNaN(ny)) ? nx - ny : x.localeCompare(y);
+        return ascending ? order : -order;
+      });
+      ascending = !ascending;
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+{{end}}`))
+
+// htmlErrorKind is one row of the failure breakdown in the HTML report.
+type htmlErrorKind struct {
+	Kind  models.ErrorKind
+	Count int
+}
+
+// htmlTag is one row of the per-tag breakdown in the HTML report.
+type htmlTag struct {
+	Tag string
+	models.Tally
+}
+
+// HTMLExporter writes a self-contained HTML report with sortable tables.
+type HTMLExporter struct{}
+
+// Format returns "html".
+func (HTMLExporter) Format() string { return "html" }
+
+// Export writes basePath + ".html".
+func (HTMLExporter) Export(fs FileSystem, basePath string, pages PageSource) ([]string, error) {
+	summary, err := summarize(pages)
+	if err != nil {
+		return nil, err
+	}
+
+	errorKinds := make([]htmlErrorKind, 0, len(summary.ErrorKinds))
+	for _, kind := range summary.SortedErrorKinds() {
+		errorKinds = append(errorKinds, htmlErrorKind{Kind: kind, Count: summary.ErrorKinds[kind]})
+	}
+
+	tags := make([]htmlTag, 0, len(summary.Tags))
+	for _, tag := range summary.SortedTags() {
+		tags = append(tags, htmlTag{Tag: tag, Tally: *summary.Tags[tag]})
+	}
+
+	path := basePath + ".html"
+	err = writeExportFile(fs, path, func(w *bufio.Writer) error {
+		err := htmlReport.ExecuteTemplate(w, "header", map[string]any{
+			"Generated":  time.Now(),
+			"Summary":    summary,
+			"ErrorKinds": errorKinds,
+			"Tags":       tags,
+		})
+		if err != nil {
+			return err
+		}
+
+		index := 0
+		err = pages(func(page *models.Page) error {
+			index++
+			return htmlReport.ExecuteTemplate(w, "row", map[string]any{
+				"Index":  index,
+				"Page":   page,
+				"Status": pageStatus(page),
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		return htmlReport.ExecuteTemplate(w, "footer", nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
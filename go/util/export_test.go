@@ -0,0 +1,238 @@
+package util_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"go-scraper/models"
+	"go-scraper/util"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exportPages returns a successful and a failed page for export tests.
+func exportPages() []*models.Page {
+	return []*models.Page{
+		{
+			URL:       "https://a.com/?q=1&r=2",
+			Title:     "A | B",
+			Links:     []string{"https://a.com/x", "https://a.com/y"},
+			Images:    []string{"https://a.com/logo.png"},
+			Tags:      []string{"news", "de"},
+			Fields:    map[string]any{"price": "$9.99", "colors": []string{"red", "blue"}},
+			TimeStamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			URL:       "https://b.com",
+			Error:     "fetch failed: timeout",
+			ErrorKind: models.ErrorKindTimeout,
+			TimeStamp: time.Date(2025, 1, 2, 3, 4, 6, 0, time.UTC),
+		},
+	}
+}
+
+func TestExportResults_AllFormats(t *testing.T) {
+	fs := newMockFS()
+	paths, err := util.ExportResults(fs, "out/results", util.ExportFormats(), util.SlicePages(exportPages()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"out/results.csv", "out/results-links.csv", "out/results.html", "out/results.json",
+		"out/results.md", "out/results.ndjson", "out/results.xml",
+	}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected files %v, got %v", expected, paths)
+	}
+	for _, path := range paths {
+		if len(fs.files[path]) == 0 {
+			t.Errorf("expected %s to have content", path)
+		}
+	}
+}
+
+func TestExportResults_UnknownFormat(t *testing.T) {
+	fs := newMockFS()
+	_, err := util.ExportResults(fs, "out/results", []string{"json", "pdf"}, util.SlicePages(exportPages()))
+	if err == nil || !strings.Contains(err.Error(), "pdf") {
+		t.Fatalf("expected unknown format error, got %v", err)
+	}
+	if len(fs.files) != 0 {
+		t.Errorf("expected no files to be written, got %d", len(fs.files))
+	}
+}
+
+func TestJSONExporter_MatchesMarshalIndent(t *testing.T) {
+	fs := newMockFS()
+	pages := exportPages()
+	if _, err := (util.JSONExporter{}).Export(fs, "out/results", util.SlicePages(pages)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected, _ := json.MarshalIndent(pages, "", "  ")
+	if got := string(fs.files["out/results.json"]); got != string(expected) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestJSONExporter_ReadsStream(t *testing.T) {
+	fs := newMockFS()
+	stream, _ := util.CreateResultsStream(fs, fakeTimeProvider{}, "out")
+	for _, page := range exportPages() {
+		_ = stream.Write(page)
+	}
+	_ = stream.Close()
+
+	paths, err := (util.JSONExporter{}).Export(fs, util.StreamBasePath(stream.Path()), util.StreamPages(fs, stream.Path()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var pages []models.Page
+	if err := json.Unmarshal(fs.files[paths[0]], &pages); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(pages) != 2 || pages[1].ErrorKind != models.ErrorKindTimeout {
+		t.Errorf("unexpected pages: %+v", pages)
+	}
+}
+
+func TestCSVExporter(t *testing.T) {
+	fs := newMockFS()
+	if _, err := (util.CSVExporter{}).Export(fs, "out/results", util.SlicePages(exportPages())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows := strings.Split(strings.TrimSpace(string(fs.files["out/results.csv"])), "\n")
+	if len(rows) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d", len(rows))
+	}
+	if !strings.HasSuffix(rows[0], ",tags,field:colors,field:price") {
+		t.Errorf("expected field columns in header, got %q", rows[0])
+	}
+	if rows[1] != "https://a.com/?q=1&r=2,A | B,true,,,,0,,,2,1,2025-01-02T03:04:05Z,news;de,red;blue,$9.99" {
+		t.Errorf("unexpected page row %q", rows[1])
+	}
+	if !strings.HasPrefix(rows[2], "https://b.com,,false,timeout,fetch failed: timeout,") {
+		t.Errorf("unexpected failed page row %q", rows[2])
+	}
+
+	links := strings.Split(strings.TrimSpace(string(fs.files["out/results-links.csv"])), "\n")
+	expected := []string{
+		"page_url,type,url",
+		"https://a.com/?q=1&r=2,link,https://a.com/x",
+		"https://a.com/?q=1&r=2,link,https://a.com/y",
+		"https://a.com/?q=1&r=2,image,https://a.com/logo.png",
+	}
+	if strings.Join(links, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected links table:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(links, "\n"))
+	}
+}
+
+func TestNDJSONExporter(t *testing.T) {
+	fs := newMockFS()
+	if _, err := (util.NDJSONExporter{}).Export(fs, "out/results", util.SlicePages(exportPages())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	count := 0
+	err := util.ReadResultsStream(fs, "out/results.ndjson", func(*models.Page) error {
+		count++
+		return nil
+	})
+	if err != nil || count != 2 {
+		t.Errorf("expected 2 readable pages, got %d (err %v)", count, err)
+	}
+}
+
+func TestXMLExporter(t *testing.T) {
+	fs := newMockFS()
+	if _, err := (util.XMLExporter{}).Export(fs, "out/results", util.SlicePages(exportPages())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		Pages []struct {
+			URL       string   `xml:"url"`
+			ErrorKind string   `xml:"errorKind"`
+			Links     []string `xml:"links>link"`
+			Fields    []struct {
+				Name   string   `xml:"name,attr"`
+				Values []string `xml:"value"`
+			} `xml:"fields>field"`
+		} `xml:"page"`
+	}
+	if err := xml.Unmarshal(fs.files["out/results.xml"], &doc); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if len(doc.Pages) != 2 || doc.Pages[0].URL != "https://a.com/?q=1&r=2" || len(doc.Pages[0].Links) != 2 || doc.Pages[1].ErrorKind != "timeout" {
+		t.Errorf("unexpected XML content: %+v", doc)
+	}
+	if fields := doc.Pages[0].Fields; len(fields) != 2 || fields[0].Name != "colors" || len(fields[0].Values) != 2 || fields[1].Values[0] != "$9.99" {
+		t.Errorf("unexpected XML fields: %+v", fields)
+	}
+}
+
+func TestMarkdownExporter(t *testing.T) {
+	fs := newMockFS()
+	if _, err := (util.MarkdownExporter{}).Export(fs, "out/results", util.SlicePages(exportPages())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := string(fs.files["out/results.md"])
+	for _, want := range []string{
+		"- **Successful:** 1",
+		"  - `timeout`: 1",
+		`| 1 | https://a.com/?q=1&r=2 | A \| B | ok | 2 | 1 |`,
+		"| 2 | https://b.com |  | timeout | 0 | 0 |  |",
+		"| news | 1 | 1 |",
+		"- https://b.com: fetch failed: timeout",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected report to contain %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestHTMLExporter(t *testing.T) {
+	fs := newMockFS()
+	if _, err := (util.HTMLExporter{}).Export(fs, "out/results", util.SlicePages(exportPages())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := string(fs.files["out/results.html"])
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"https://a.com/?q=1&amp;r=2",
+		`<tr class="failed">`,
+		"Failed: timeout",
+		"<td>news, de</td>",
+		"table.sortable",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected report to contain %q", want)
+		}
+	}
+	if strings.Contains(report, "<script src=") || strings.Contains(report, `rel="stylesheet"`) {
+		t.Error("expected a self-contained report without external resources")
+	}
+}
+
+func TestLatestPages(t *testing.T) {
+	first := []*models.Page{{URL: "a", Error: "boom"}, {URL: "b"}}
+	second := []*models.Page{{URL: "a", Title: "retried"}, {URL: "c"}}
+
+	var merged []*models.Page
+	err := util.LatestPages(util.ConcatPages(util.SlicePages(first), util.SlicePages(second)))(func(page *models.Page) error {
+		merged = append(merged, page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(merged) != 3 || merged[0].URL != "b" || merged[1].URL != "a" || merged[1].Title != "retried" || merged[2].URL != "c" {
+		t.Errorf("unexpected merged pages: %+v", merged)
+	}
+}
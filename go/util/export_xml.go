@@ -0,0 +1,95 @@
+package util
+
+import (
+	"bufio"
+	"encoding/xml"
+	"go-scraper/models"
+	"maps"
+	"slices"
+	"time"
+)
+
+// xmlPage is the XML representation of a models.Page.
+type xmlPage struct {
+	XMLName    xml.Name   `xml:"page"`
+	URL        string     `xml:"url"`
+	Title      string     `xml:"title"`
+	TimeStamp  time.Time  `xml:"timestamp"`
+	Error      string     `xml:"error,omitempty"`
+	ErrorKind  string     `xml:"errorKind,omitempty"`
+	HTTPStatus int        `xml:"httpStatus,omitempty"`
+	Depth      int        `xml:"depth,omitempty"`
+	ParentURL  string     `xml:"parentUrl,omitempty"`
+	Tags       []string   `xml:"tags>tag"`
+	Attempts   int        `xml:"attempts,omitempty"`
+	Links      []string   `xml:"links>link"`
+	Images     []string   `xml:"images>image"`
+	OtherLinks *xmlLinks  `xml:"otherLinks,omitempty"`
+	Fields     []xmlField `xml:"fields>field"`
+}
+
+// xmlField is a custom field with one <value> element per value.
+type xmlField struct {
+	Name   string   `xml:"name,attr"`
+	Values []string `xml:"value"`
+}
+
+// xmlLinks wraps a list of <link> elements so the container can be omitted when empty.
+type xmlLinks struct {
+	Links []string `xml:"link"`
+}
+
+// XMLExporter writes a <pages> document with one <page> element per page.
+type XMLExporter struct{}
+
+// Format returns "xml".
+func (XMLExporter) Format() string { return "xml" }
+
+// Export writes basePath + ".xml".
+func (XMLExporter) Export(fs FileSystem, basePath string, pages PageSource) ([]string, error) {
+	path := basePath + ".xml"
+	err := writeExportFile(fs, path, func(w *bufio.Writer) error {
+		if _, err := w.WriteString(xml.Header + "<pages>\n"); err != nil {
+			return err
+		}
+
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("  ", "  ")
+		err := pages(func(page *models.Page) error {
+			var otherLinks *xmlLinks
+			if len(page.OtherLinks) > 0 {
+				otherLinks = &xmlLinks{Links: page.OtherLinks}
+			}
+			var fields []xmlField
+			for _, name := range slices.Sorted(maps.Keys(page.Fields)) {
+				fields = append(fields, xmlField{Name: name, Values: fieldValues(page.Fields[name])})
+			}
+			return encoder.Encode(xmlPage{
+				URL:        page.URL,
+				Title:      page.Title,
+				TimeStamp:  page.TimeStamp,
+				Error:      page.Error,
+				ErrorKind:  string(page.ErrorKind),
+				HTTPStatus: page.HTTPStatus,
+				Depth:      page.Depth,
+				ParentURL:  page.ParentURL,
+				Tags:       page.Tags,
+				Attempts:   page.Attempts,
+				Links:      page.Links,
+				Images:     page.Images,
+				OtherLinks: otherLinks,
+				Fields:     fields,
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = w.WriteString("\n</pages>\n")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
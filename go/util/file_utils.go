@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"go-scraper/models"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -18,11 +19,19 @@ import (
 //   - ReadFile: Read entire file contents into memory
 //   - WriteFile: Write data to a file with specified permissions
 //   - MakeDir: Create a directory and all necessary parent directories
+//   - Create: Create or truncate a file for incremental writing
+//   - Open: Open a file for incremental reading
+//   - Rename: Move a file, replacing the target (used to replace files atomically)
+//   - Remove: Delete a file
 type FileSystem interface {
 	Stat(name string) (os.FileInfo, error)
 	ReadFile(name string) ([]byte, error)
 	WriteFile(name string, data []byte, perm os.FileMode) error
 	MakeDir(path string) error
+	Create(name string) (io.WriteCloser, error)
+	Open(name string) (io.ReadCloser, error)
+	Rename(oldName, newName string) error
+	Remove(name string) error
 }
 
 // OSFileSystem is the production implementation of FileSystem that delegates
@@ -45,6 +54,18 @@ func (OSFileSystem) MakeDir(path string) error {
 	return os.MkdirAll(path, os.ModePerm)
 }
 
+// Create creates or truncates the named file for writing.
+func (OSFileSystem) Create(name string) (io.WriteCloser, error) { return os.Create(name) }
+
+// Open opens the named file for reading.
+func (OSFileSystem) Open(name string) (io.ReadCloser, error) { return os.Open(name) }
+
+// Rename moves a file, replacing newName if it exists.
+func (OSFileSystem) Rename(oldName, newName string) error { return os.Rename(oldName, newName) }
+
+// Remove deletes the named file.
+func (OSFileSystem) Remove(name string) error { return os.Remove(name) }
+
 // TimeProvider abstracts time generation for deterministic testing.
 // This allows tests to control timestamps without relying on the system clock.
 //
@@ -61,16 +82,17 @@ type RealTimeProvider struct{}
 // NowUnixMilli returns the current time as Unix milliseconds.
 func (RealTimeProvider) NowUnixMilli() int64 { return time.Now().UnixMilli() }
 
-// GetURLsFromFile reads URLs from a JSON file. If the file doesn't exist,
-// it creates an empty JSON array file and returns an empty slice.
-// Returns an error if the file cannot be read or contains invalid JSON.
-func GetURLsFromFile(fs FileSystem, configFile string) ([]string, error) {
+// GetTargetsFromFile reads a JSON URL list whose entries are URL strings or target
+// objects with per-URL options. If the file doesn't exist, it creates an empty JSON
+// array file and returns an empty slice. Returns an error if the file cannot be read
+// or contains invalid JSON.
+func GetTargetsFromFile(fs FileSystem, configFile string) ([]models.Target, error) {
 	if _, err := fs.Stat(configFile); os.IsNotExist(err) {
 		// File doesn't exist, create empty JSON array
 		if createErr := fs.WriteFile(configFile, []byte("[]"), 0644); createErr != nil {
 			return nil, fmt.Errorf("failed to create URLs file %s: %w", configFile, createErr)
 		}
-		return []string{}, nil
+		return []models.Target{}, nil
 	}
 
 	data, err := fs.ReadFile(configFile)
@@ -78,42 +100,68 @@ func GetURLsFromFile(fs FileSystem, configFile string) ([]string, error) {
 		return nil, fmt.Errorf("failed to read URLs from %s: %w", configFile, err)
 	}
 
-	var urls []string
-	if err := json.Unmarshal(data, &urls); err != nil {
+	var targets []models.Target
+	if err := json.Unmarshal(data, &targets); err != nil {
 		return nil, fmt.Errorf("invalid JSON format in %s: %w", configFile, err)
 	}
 
-	return urls, nil
+	return targets, nil
 }
 
-// SaveResultsToFile saves the given pages to a timestamped JSON file inside the specified folder.
-// It uses injected filesystem and time providers for testability.
-// The filename format is "scrape-results-{timestamp}.json" where timestamp is Unix milliseconds.
+// SaveLinkReport saves the report of a link check to a timestamped JSON file inside the
+// specified folder, named "link-report-{timestamp}.json".
 // Returns the full path to the saved file or an error if the operation fails.
-func SaveResultsToFile(fs FileSystem, tp TimeProvider, folder string, pages []*models.Page) (string, error) {
-	if len(pages) == 0 {
-		return "", fmt.Errorf("cannot save results: no pages provided")
-	}
-
-	// Ensure target folder exists
+func SaveLinkReport(fs FileSystem, tp TimeProvider, folder string, report *models.LinkReport) (string, error) {
 	if err := fs.MakeDir(folder); err != nil {
 		return "", fmt.Errorf("failed to create output directory %s: %w", folder, err)
 	}
+	fullPath := filepath.Join(folder, fmt.Sprintf("link-report-%d.json", tp.NowUnixMilli()))
 
-	// Build full file path with timestamp
-	filename := fmt.Sprintf("scrape-results-%d.json", tp.NowUnixMilli())
-	fullPath := filepath.Join(folder, filename)
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize link report to JSON: %w", err)
+	}
+	if err := fs.WriteFile(fullPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write link report to %s: %w", fullPath, err)
+	}
+	return fullPath, nil
+}
 
-	// Serialize to JSON with indentation
-	data, err := json.MarshalIndent(pages, "", "  ")
+// AddURLsToFile appends URLs to the JSON URL list in configFile, creating the
+// file if it doesn't exist. URLs that are already present are skipped; existing
+// target objects keep their options. Returns the number of URLs that were actually added.
+func AddURLsToFile(fs FileSystem, configFile string, newURLs []string) (int, error) {
+	targets, err := GetTargetsFromFile(fs, configFile)
 	if err != nil {
-		return "", fmt.Errorf("failed to serialize results to JSON: %w", err)
+		return 0, err
 	}
 
-	// Write file to disk
-	if err := fs.WriteFile(fullPath, data, 0644); err != nil {
-		return "", fmt.Errorf("failed to write results to %s: %w", fullPath, err)
+	existing := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		existing[target.URL] = struct{}{}
 	}
 
-	return fullPath, nil
+	added := 0
+	for _, u := range newURLs {
+		if _, ok := existing[u]; ok {
+			continue
+		}
+		existing[u] = struct{}{}
+		targets = append(targets, models.Target{URL: u})
+		added++
+	}
+
+	if added == 0 {
+		return 0, nil
+	}
+
+	data, err := json.MarshalIndent(targets, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize URLs to JSON: %w", err)
+	}
+	if err := fs.WriteFile(configFile, data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write URLs to %s: %w", configFile, err)
+	}
+
+	return added, nil
 }
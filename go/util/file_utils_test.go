@@ -1,10 +1,13 @@
 package util_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go-scraper/models"
 	"go-scraper/util"
+	"io"
 	"os"
 	"testing"
 )
@@ -49,14 +52,64 @@ func (m *mockFileSystem) MakeDir(_ string) error {
 	return nil
 }
 
+func (m *mockFileSystem) Create(name string) (io.WriteCloser, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.files[name] = nil
+	return &mockFile{fs: m, name: name}, nil
+}
+
+func (m *mockFileSystem) Open(name string) (io.ReadCloser, error) {
+	data, err := m.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *mockFileSystem) Rename(oldName, newName string) error {
+	if m.err != nil {
+		return m.err
+	}
+	data, ok := m.files[oldName]
+	if !ok {
+		return os.ErrNotExist
+	}
+	m.files[newName] = data
+	delete(m.files, oldName)
+	return nil
+}
+
+func (m *mockFileSystem) Remove(name string) error {
+	if _, ok := m.files[name]; !ok {
+		return os.ErrNotExist
+	}
+	delete(m.files, name)
+	return nil
+}
+
+// mockFile appends writes directly to the mock filesystem so partial content is visible.
+type mockFile struct {
+	fs   *mockFileSystem
+	name string
+}
+
+func (f *mockFile) Write(p []byte) (int, error) {
+	f.fs.files[f.name] = append(f.fs.files[f.name], p...)
+	return len(p), nil
+}
+
+func (f *mockFile) Close() error { return nil }
+
 // fakeTimeProvider returns a fixed timestamp for reproducible tests.
 type fakeTimeProvider struct{}
 
 func (fakeTimeProvider) NowUnixMilli() int64 { return 1234567890 }
 
-func TestGetURLsFromFile_CreateIfMissing(t *testing.T) {
+func TestGetTargetsFromFile_CreateIfMissing(t *testing.T) {
 	fs := newMockFS()
-	urls, err := util.GetURLsFromFile(fs, "urls.json")
+	urls, err := util.GetTargetsFromFile(fs, "urls.json")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -68,11 +121,11 @@ func TestGetURLsFromFile_CreateIfMissing(t *testing.T) {
 	}
 }
 
-func TestGetURLsFromFile_ReadExisting(t *testing.T) {
+func TestGetTargetsFromFile_ReadExisting(t *testing.T) {
 	fs := newMockFS()
 	fs.files["urls.json"] = []byte(`["https://a.com", "https://b.com"]`)
 
-	urls, err := util.GetURLsFromFile(fs, "urls.json")
+	urls, err := util.GetTargetsFromFile(fs, "urls.json")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -81,54 +134,110 @@ func TestGetURLsFromFile_ReadExisting(t *testing.T) {
 	}
 }
 
-func TestGetURLsFromFile_InvalidJSON(t *testing.T) {
+func TestGetTargetsFromFile_InvalidJSON(t *testing.T) {
 	fs := newMockFS()
 	fs.files["urls.json"] = []byte(`{not valid}`)
-	_, err := util.GetURLsFromFile(fs, "urls.json")
+	_, err := util.GetTargetsFromFile(fs, "urls.json")
 	if err == nil {
 		t.Fatal("expected error for invalid JSON, got nil")
 	}
 }
 
-func TestSaveResultsToFile_Success(t *testing.T) {
+func TestJSONExporter_EmptyPages(t *testing.T) {
 	fs := newMockFS()
-	tp := fakeTimeProvider{}
-	pages := []*models.Page{
-		{URL: "https://a.com", Title: "A"},
-		{URL: "https://b.com", Title: "B"},
+	paths, err := (util.JSONExporter{}).Export(fs, "output/results", util.SlicePages([]*models.Page{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paths) != 1 || string(fs.files[paths[0]]) != "[]" {
+		t.Errorf("expected an empty JSON array, got %v: %q", paths, fs.files["output/results.json"])
+	}
+}
+
+func TestJSONExporter_WriteError(t *testing.T) {
+	fs := newMockFS()
+	fs.err = errors.New("write failure")
+	pages := []*models.Page{{URL: "https://a.com"}}
+
+	_, err := (util.JSONExporter{}).Export(fs, "output/results", util.SlicePages(pages))
+	if err == nil {
+		t.Fatal("expected write error, got nil")
 	}
+}
 
-	filename, err := util.SaveResultsToFile(fs, tp, "output", pages)
+func TestSaveLinkReport(t *testing.T) {
+	fs := newMockFS()
+	tp := fakeTimeProvider{}
+	report := models.NewLinkReport([]models.LinkCheck{{
+		LinkTarget: models.LinkTarget{URL: "https://a.com/gone", Sources: []string{"https://a.com"}},
+		StatusCode: 404,
+		ErrorKind:  models.ErrorKindHTTPStatus,
+	}})
+
+	filename, err := util.SaveLinkReport(fs, tp, "output", report)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	expected := fmt.Sprintf("output/scrape-results-%d.json", tp.NowUnixMilli())
+	expected := fmt.Sprintf("output/link-report-%d.json", tp.NowUnixMilli())
 	if filename != expected {
 		t.Errorf("expected filename %s, got %s", expected, filename)
 	}
-	if _, ok := fs.files[filename]; !ok {
-		t.Errorf("expected file %s to be written", filename)
+	var saved models.LinkReport
+	if err := json.Unmarshal(fs.files[filename], &saved); err != nil {
+		t.Fatalf("invalid report JSON: %v", err)
+	}
+	if saved.Broken != 1 || len(saved.Sources) != 1 || saved.Sources[0].Page != "https://a.com" {
+		t.Errorf("unexpected saved report: %+v", saved)
 	}
 }
 
-func TestSaveResultsToFile_EmptyPages(t *testing.T) {
+func TestAddURLsToFile_SkipsDuplicates(t *testing.T) {
 	fs := newMockFS()
-	tp := fakeTimeProvider{}
-	_, err := util.SaveResultsToFile(fs, tp, "output", []*models.Page{})
-	if err == nil {
-		t.Fatal("expected error for empty pages, got nil")
+	fs.files["urls.json"] = []byte(`["https://a.com"]`)
+
+	added, err := util.AddURLsToFile(fs, "urls.json", []string{"https://a.com", "https://b.com", "https://b.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("expected 1 URL added, got %d", added)
+	}
+
+	targets, err := util.GetTargetsFromFile(fs, "urls.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if urls := models.TargetURLs(targets); len(urls) != 2 || urls[1] != "https://b.com" {
+		t.Errorf("unexpected URLs after add: %#v", targets)
 	}
 }
 
-func TestSaveResultsToFile_WriteError(t *testing.T) {
+func TestAddURLsToFile_CreateIfMissing(t *testing.T) {
 	fs := newMockFS()
-	fs.err = errors.New("write failure")
-	tp := fakeTimeProvider{}
-	pages := []*models.Page{{URL: "https://a.com"}}
 
-	_, err := util.SaveResultsToFile(fs, tp, "output", pages)
-	if err == nil {
-		t.Fatal("expected write error, got nil")
+	added, err := util.AddURLsToFile(fs, "urls.json", []string{"https://a.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("expected 1 URL added, got %d", added)
+	}
+	if string(fs.files["urls.json"]) == "[]" {
+		t.Error("expected URL to be written to the new file")
+	}
+}
+
+func TestAddURLsToFile_KeepsTargetOptions(t *testing.T) {
+	fs := newMockFS()
+	fs.files["urls.json"] = []byte(`[{"url": "https://a.com", "tags": ["news"]}]`)
+
+	if _, err := util.AddURLsToFile(fs, "urls.json", []string{"https://b.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "[\n  {\n    \"url\": \"https://a.com\",\n    \"tags\": [\n      \"news\"\n    ]\n  },\n  \"https://b.com\"\n]"
+	if got := string(fs.files["urls.json"]); got != expected {
+		t.Errorf("unexpected URL file:\n%s", got)
 	}
 }
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-scraper/models"
+	"path/filepath"
+	"time"
+)
+
+// LastRunFile is the name of the file in the results directory describing the latest run of watch mode
+const LastRunFile = "last-run.json"
+
+// LastRun describes the latest run of watch mode, so its outcome can be checked
+// (e.g. by monitoring) without reading the console output.
+type LastRun struct {
+	Run          int             `json:"run"`                   // Number of the run since watch mode was started
+	StartedAt    time.Time       `json:"startedAt"`             // Start of the run
+	FinishedAt   time.Time       `json:"finishedAt"`            // End of the run
+	NextRunAt    time.Time       `json:"nextRunAt"`             // Scheduled start of the next run
+	Summary      *models.Summary `json:"summary,omitempty"`     // Outcome of the scraped pages
+	Changed      bool            `json:"changed"`               // The results differed from the previous run and were saved
+	Added        int             `json:"added"`                 // Pages not present in the previous run
+	Removed      int             `json:"removed"`               // Pages of the previous run no longer present
+	ChangedPages int             `json:"changedPages"`          // Pages whose status, title, links or images changed
+	ResultFiles  []string        `json:"resultFiles,omitempty"` // Files the results were saved to
+	Error        string          `json:"error,omitempty"`       // Why the URL list could not be scraped
+}
+
+// LastRunPath returns the location of the last run file in the results directory.
+func LastRunPath(folder string) string {
+	return filepath.Join(folder, LastRunFile)
+}
+
+// LoadLastRun reads the description of the latest watch run.
+func LoadLastRun(fs FileSystem, path string) (*LastRun, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last run %s: %w", path, err)
+	}
+	var run LastRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("invalid last run %s: %w", path, err)
+	}
+	return &run, nil
+}
+
+// Save writes the description of the run as JSON, creating the results directory if needed.
+func (r *LastRun) Save(fs FileSystem, path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize last run: %w", err)
+	}
+	if err := fs.MakeDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to write last run %s: %w", path, err)
+	}
+	if err := fs.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write last run %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package util_test
+
+import (
+	"go-scraper/models"
+	"go-scraper/util"
+	"testing"
+	"time"
+)
+
+func TestLastRun_SaveAndLoad(t *testing.T) {
+	fs := newMockFS()
+	started := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	run := &util.LastRun{
+		Run:          3,
+		StartedAt:    started,
+		FinishedAt:   started.Add(42 * time.Second),
+		NextRunAt:    started.Add(time.Hour),
+		Summary:      &models.Summary{Total: 2, Successful: 1, ErrorKinds: map[models.ErrorKind]int{models.ErrorKindTimeout: 1}},
+		Changed:      true,
+		ChangedPages: 1,
+		ResultFiles:  []string{"out/scrape-results-1.json"},
+	}
+
+	path := util.LastRunPath("out")
+	if err := run.Save(fs, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := util.LoadLastRun(fs, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Run != 3 || !loaded.Changed || loaded.ChangedPages != 1 || !loaded.NextRunAt.Equal(run.NextRunAt) {
+		t.Errorf("unexpected last run: %+v", loaded)
+	}
+	if loaded.Summary == nil || loaded.Summary.ErrorKinds[models.ErrorKindTimeout] != 1 {
+		t.Errorf("expected the summary to round-trip, got %+v", loaded.Summary)
+	}
+	if len(loaded.ResultFiles) != 1 || loaded.ResultFiles[0] != "out/scrape-results-1.json" {
+		t.Errorf("unexpected result files: %v", loaded.ResultFiles)
+	}
+}
+
+func TestLoadLastRun_Missing(t *testing.T) {
+	if _, err := util.LoadLastRun(newMockFS(), util.LastRunPath("out")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
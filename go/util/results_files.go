@@ -0,0 +1,89 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-scraper/models"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resultFilePattern matches the names of saved results and result streams:
+// scrape-results-{timestamp}.json, scrape-results-{timestamp}.ndjson and the
+// scrape-results-{timestamp}-merged.json export of a resumed run.
+var resultFilePattern = regexp.MustCompile(`^scrape-results-(\d+)(-merged)?\.(json|ndjson)$`)
+
+// LoadResults reads all pages of a results file: an NDJSON stream (.ndjson) or a
+// JSON array of pages (any other extension).
+func LoadResults(fs FileSystem, path string) ([]*models.Page, error) {
+	if strings.EqualFold(filepath.Ext(path), resultsStreamExt) {
+		var pages []*models.Page
+		err := ReadResultsStream(fs, path, func(page *models.Page) error {
+			pages = append(pages, page)
+			return nil
+		})
+		return pages, err
+	}
+
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results %s: %w", path, err)
+	}
+	var pages []*models.Page
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return nil, fmt.Errorf("invalid results JSON in %s: %w", path, err)
+	}
+	return pages, nil
+}
+
+// LatestResultFiles selects the result files of the n most recent runs from the file
+// names of a results directory, oldest first. Runs are ordered by the timestamp in the
+// name. Of the files of one run the most complete is chosen: the merged export of a
+// resumed run, then the JSON export, then the NDJSON stream. Other files are ignored.
+func LatestResultFiles(names []string, n int) []string {
+	type run struct {
+		timestamp int64
+		name      string
+		rank      int // lower is preferred
+	}
+
+	runs := make(map[int64]run)
+	for _, name := range names {
+		match := resultFilePattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		rank := 2
+		switch {
+		case match[2] != "":
+			rank = 0
+		case match[3] == "json":
+			rank = 1
+		}
+		if current, ok := runs[timestamp]; !ok || rank < current.rank {
+			runs[timestamp] = run{timestamp: timestamp, name: name, rank: rank}
+		}
+	}
+
+	ordered := make([]run, 0, len(runs))
+	for _, r := range runs {
+		ordered = append(ordered, r)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].timestamp < ordered[j].timestamp })
+	if len(ordered) > n {
+		ordered = ordered[len(ordered)-n:]
+	}
+
+	files := make([]string, len(ordered))
+	for i, r := range ordered {
+		files[i] = r.name
+	}
+	return files
+}
@@ -0,0 +1,60 @@
+package util_test
+
+import (
+	"go-scraper/models"
+	"go-scraper/util"
+	"reflect"
+	"testing"
+)
+
+func TestLoadResults(t *testing.T) {
+	fs := newMockFS()
+	fs.files["out/run.json"] = []byte(`[{"url": "https://a.com"}, {"url": "https://b.com"}]`)
+	fs.files["out/run.ndjson"] = []byte("{\"url\": \"https://a.com\"}\n{\"url\": \"https://b.com\"}\n")
+	fs.files["out/bad.json"] = []byte(`{"url": "https://a.com"}`)
+
+	for _, path := range []string{"out/run.json", "out/run.ndjson"} {
+		pages, err := util.LoadResults(fs, path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+		if urls := pageURLs(pages); !reflect.DeepEqual(urls, []string{"https://a.com", "https://b.com"}) {
+			t.Errorf("%s: unexpected pages %v", path, urls)
+		}
+	}
+
+	if _, err := util.LoadResults(fs, "out/bad.json"); err == nil {
+		t.Error("expected error for a JSON object instead of an array")
+	}
+}
+
+func TestLatestResultFiles(t *testing.T) {
+	names := []string{
+		"checkpoint.json",
+		"scrape-results-100.ndjson",
+		"scrape-results-300.ndjson",
+		"scrape-results-300.json",
+		"scrape-results-300.csv",
+		"scrape-results-300-links.csv",
+		"scrape-results-200.ndjson",
+		"scrape-results-400.ndjson",
+		"scrape-results-400-merged.json",
+		"scrape-results-400-merged.csv",
+	}
+
+	expected := []string{"scrape-results-300.json", "scrape-results-400-merged.json"}
+	if files := util.LatestResultFiles(names, 2); !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+	if files := util.LatestResultFiles(names[:2], 2); !reflect.DeepEqual(files, []string{"scrape-results-100.ndjson"}) {
+		t.Errorf("expected the only run, got %v", files)
+	}
+}
+
+func pageURLs(pages []*models.Page) []string {
+	urls := make([]string, len(pages))
+	for i, page := range pages {
+		urls[i] = page.URL
+	}
+	return urls
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-scraper/models"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// resultsStreamExt is the file extension of streamed results (one JSON page per line)
+	resultsStreamExt = ".ndjson"
+	// maxStreamLineBytes bounds the size of a single page when reading a results stream
+	maxStreamLineBytes = 64 * 1024 * 1024
+)
+
+// ResultsStream writes scraped pages as newline-delimited JSON (NDJSON).
+// Every page is encoded and written with a single write call as soon as it is
+// received, so results written before a crash or interruption stay on disk.
+// The first write error is kept; later writes are skipped and Close reports it.
+// A ResultsStream is not safe for concurrent use (the runners serialize writes).
+type ResultsStream struct {
+	file io.WriteCloser
+	path string
+	err  error
+}
+
+// CreateResultsStream creates a timestamped NDJSON results file inside folder.
+// The filename format is "scrape-results-{timestamp}.ndjson" where timestamp is Unix milliseconds.
+func CreateResultsStream(fs FileSystem, tp TimeProvider, folder string) (*ResultsStream, error) {
+	if err := fs.MakeDir(folder); err != nil {
+		return nil, fmt.Errorf("failed to create output directory %s: %w", folder, err)
+	}
+
+	path := filepath.Join(folder, fmt.Sprintf("scrape-results-%d%s", tp.NowUnixMilli(), resultsStreamExt))
+	file, err := fs.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create results stream %s: %w", path, err)
+	}
+
+	return &ResultsStream{file: file, path: path}, nil
+}
+
+// Path returns the location of the stream file.
+func (s *ResultsStream) Path() string { return s.path }
+
+// StreamBasePath returns a stream path without its extension, the base name for
+// exports of the same run (see ExportResults).
+func StreamBasePath(path string) string { return strings.TrimSuffix(path, resultsStreamExt) }
+
+// Write appends a page as one JSON line. It implements core.ResultSink.
+func (s *ResultsStream) Write(page *models.Page) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	line, err := json.Marshal(page)
+	if err != nil {
+		s.err = fmt.Errorf("failed to serialize page %s: %w", page.URL, err)
+		return s.err
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		s.err = fmt.Errorf("failed to write results to %s: %w", s.path, err)
+		return s.err
+	}
+
+	return nil
+}
+
+// Close closes the stream file and returns the first error that occurred while writing.
+func (s *ResultsStream) Close() error {
+	closeErr := s.file.Close()
+	if s.err != nil {
+		return s.err
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close results stream %s: %w", s.path, closeErr)
+	}
+	return nil
+}
+
+// ReadResultsStream reads an NDJSON results file and calls fn for every page in order.
+// Pages are decoded one at a time, so memory usage does not grow with the file size.
+// Reading stops at the first error returned by fn. A last line that is not terminated
+// by a newline and cannot be decoded is skipped: it is the page that was being written
+// when the process died.
+func ReadResultsStream(fs FileSystem, path string, fn func(page *models.Page) error) error {
+	file, err := fs.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open results stream %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineBytes)
+	var unterminated bool // whether the current line is the last one and lacks its newline
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		unterminated = atEOF && len(data) > 0 && bytes.IndexByte(data, '\n') < 0
+		return bufio.ScanLines(data, atEOF)
+	})
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var page models.Page
+		if err := json.Unmarshal(line, &page); err != nil {
+			if unterminated {
+				break
+			}
+			return fmt.Errorf("invalid page on line %d of %s: %w", lineNumber, path, err)
+		}
+		if err := fn(&page); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read results stream %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package util_test
+
+import (
+	"go-scraper/models"
+	"go-scraper/util"
+	"strings"
+	"testing"
+)
+
+func TestResultsStream_WriteAndRead(t *testing.T) {
+	fs := newMockFS()
+	stream, err := util.CreateResultsStream(fs, fakeTimeProvider{}, "out")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(stream.Path(), "scrape-results-1234567890.ndjson") {
+		t.Errorf("unexpected stream path %q", stream.Path())
+	}
+
+	pages := []*models.Page{{URL: "https://a.com", Title: "A"}, {URL: "https://b.com", Error: "boom"}}
+	for _, page := range pages {
+		if err := stream.Write(page); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+
+	// Pages are on disk before the stream is closed
+	if lines := strings.Count(string(fs.files[stream.Path()]), "\n"); lines != 2 {
+		t.Errorf("expected 2 lines written before close, got %d", lines)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	var read []string
+	err = util.ReadResultsStream(fs, stream.Path(), func(page *models.Page) error {
+		read = append(read, page.URL)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if len(read) != 2 || read[0] != "https://a.com" || read[1] != "https://b.com" {
+		t.Errorf("unexpected pages read back: %v", read)
+	}
+}
+
+func TestReadResultsStream_InvalidLine(t *testing.T) {
+	fs := newMockFS()
+	fs.files["broken.ndjson"] = []byte("{\"url\":\"a\"}\nnot json\n")
+
+	err := util.ReadResultsStream(fs, "broken.ndjson", func(*models.Page) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error for line 2, got %v", err)
+	}
+}
+
+func TestReadResultsStream_PartialLastLine(t *testing.T) {
+	fs := newMockFS()
+	fs.files["crashed.ndjson"] = []byte("{\"url\":\"a\"}\n{\"url\":\"b\",\"ti")
+
+	var read []string
+	err := util.ReadResultsStream(fs, "crashed.ndjson", func(page *models.Page) error {
+		read = append(read, page.URL)
+		return nil
+	})
+	if err != nil || len(read) != 1 || read[0] != "a" {
+		t.Errorf("expected the partial last line to be skipped, got %v (%v)", read, err)
+	}
+}
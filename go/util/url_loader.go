@@ -0,0 +1,439 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"go-scraper/models"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StdinPath is the URL file name that reads the URL list from standard input.
+const StdinPath = "-"
+
+// maxSitemapDepth bounds how deeply sitemap index files may reference each other.
+const maxSitemapDepth = 3
+
+// URLFormat identifies the format of a URL list.
+type URLFormat string
+
+const (
+	// URLFormatJSON is a JSON array of URL strings.
+	URLFormatJSON URLFormat = "json"
+	// URLFormatText is one URL per line; empty lines and "#" comments are ignored.
+	URLFormatText URLFormat = "text"
+	// URLFormatCSV is a CSV table with a "url" column (or URLs in the first column).
+	URLFormatCSV URLFormat = "csv"
+	// URLFormatSitemap is a sitemap.xml or sitemap index file (optionally gzip-compressed).
+	URLFormatSitemap URLFormat = "sitemap"
+)
+
+// RejectedURL describes an entry of a URL list that was not accepted.
+type RejectedURL struct {
+	Line   int    // Line (text, CSV) or entry number (JSON, sitemap), starting at 1
+	Value  string // Rejected value as found in the input
+	Reason string // Why the value was rejected
+}
+
+// URLList is the result of loading a URL list: the valid, deduplicated targets in input
+// order plus a report of everything that was skipped.
+type URLList struct {
+	Source     string          // File path, URL or "-" for stdin
+	Format     URLFormat       // Detected format
+	Targets    []models.Target // Valid targets, with trimmed URLs and deduplicated by URL
+	Rejected   []RejectedURL   // Invalid entries
+	Duplicates int             // Number of entries skipped as duplicates
+}
+
+// URLs returns the URLs of all targets in order.
+func (l *URLList) URLs() []string {
+	return models.TargetURLs(l.Targets)
+}
+
+// URLLoader loads URL lists in JSON, plain text, CSV and sitemap format.
+// The format is detected from the file extension, or by sniffing the content
+// when the extension is unknown (e.g. for stdin).
+type URLLoader struct {
+	FS    FileSystem                          // Filesystem for local files
+	Stdin io.Reader                           // Source for the "-" path
+	Fetch func(rawURL string) ([]byte, error) // Downloads remote sitemaps; nil disables remote sources
+}
+
+// Load reads the URL list at path. A missing JSON URL file is created as an empty
+// array (see GetTargetsFromFile); other missing files are an error.
+func (l URLLoader) Load(path string) (*URLList, error) {
+	data, err := l.read(path)
+	if errors.Is(err, os.ErrNotExist) && DetectURLFormat(path) == URLFormatJSON {
+		targets, createErr := GetTargetsFromFile(l.FS, path)
+		if createErr != nil {
+			return nil, createErr
+		}
+		return &URLList{Source: path, Format: URLFormatJSON, Targets: targets}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	format := DetectURLFormat(path)
+	if format == "" {
+		format = sniffFormat(data)
+	}
+
+	list := &URLList{Source: path, Format: format}
+	collector := newURLCollector(list)
+	switch format {
+	case URLFormatJSON:
+		err = parseJSONURLs(data, collector)
+	case URLFormatCSV:
+		err = parseCSVURLs(data, collector)
+	case URLFormatSitemap:
+		err = l.parseSitemap(data, path, collector, 0)
+	default:
+		parseTextURLs(data, collector)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s URL list %s: %w", format, path, err)
+	}
+	return list, nil
+}
+
+// read returns the raw content of a local file, remote URL or stdin.
+func (l URLLoader) read(path string) ([]byte, error) {
+	switch {
+	case path == StdinPath:
+		if l.Stdin == nil {
+			return nil, errors.New("no standard input available")
+		}
+		data, err := io.ReadAll(l.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read URLs from stdin: %w", err)
+		}
+		return data, nil
+	case isRemote(path):
+		if l.Fetch == nil {
+			return nil, fmt.Errorf("remote URL lists are not supported: %s", path)
+		}
+		data, err := l.Fetch(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", path, err)
+		}
+		return data, nil
+	default:
+		if _, err := l.FS.Stat(path); os.IsNotExist(err) {
+			return nil, fmt.Errorf("URL file %s does not exist: %w", path, os.ErrNotExist)
+		}
+		data, err := l.FS.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read URLs from %s: %w", path, err)
+		}
+		return data, nil
+	}
+}
+
+// ValidateURL ensures a URL is absolute and uses the http or https scheme.
+func ValidateURL(raw string) error {
+	u, err := url.ParseRequestURI(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q: expected an absolute http(s) URL", raw)
+	}
+	return nil
+}
+
+// urlCollector validates, trims and deduplicates URLs while they are parsed.
+type urlCollector struct {
+	list *URLList
+	seen map[string]struct{}
+}
+
+// newURLCollector creates a collector that fills list.
+func newURLCollector(list *URLList) *urlCollector {
+	return &urlCollector{list: list, seen: make(map[string]struct{})}
+}
+
+// add records a candidate URL found at the given line or entry number.
+func (c *urlCollector) add(line int, value string) {
+	c.addTarget(line, value, models.Target{URL: value})
+}
+
+// addTarget records a candidate target; value is the entry as found in the input.
+func (c *urlCollector) addTarget(line int, value string, target models.Target) {
+	target.URL = strings.TrimSpace(target.URL)
+	if err := ValidateURL(target.URL); err != nil {
+		c.reject(line, value, "not an absolute http(s) URL")
+		return
+	}
+	if _, ok := c.seen[target.URL]; ok {
+		c.list.Duplicates++
+		return
+	}
+	c.seen[target.URL] = struct{}{}
+	c.list.Targets = append(c.list.Targets, target)
+}
+
+// reject records an entry that cannot be used.
+func (c *urlCollector) reject(line int, value, reason string) {
+	c.list.Rejected = append(c.list.Rejected, RejectedURL{Line: line, Value: value, Reason: reason})
+}
+
+// DetectURLFormat derives the format of a URL list from its file extension.
+// It returns "" for stdin and unknown extensions, whose format is sniffed from the content.
+func DetectURLFormat(path string) URLFormat {
+	if path == StdinPath {
+		return ""
+	}
+	if isRemote(path) {
+		if u, err := url.Parse(path); err == nil {
+			path = u.Path
+		}
+	}
+	name := strings.TrimSuffix(strings.ToLower(path), ".gz")
+	switch filepath.Ext(name) {
+	case ".json":
+		return URLFormatJSON
+	case ".txt", ".list":
+		return URLFormatText
+	case ".csv", ".tsv":
+		return URLFormatCSV
+	case ".xml":
+		return URLFormatSitemap
+	default:
+		return ""
+	}
+}
+
+// sniffFormat guesses the format from the content.
+func sniffFormat(data []byte) URLFormat {
+	if isGzip(data) {
+		return URLFormatSitemap
+	}
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return URLFormatJSON
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return URLFormatSitemap
+	}
+
+	firstLine, _, _ := bytes.Cut(trimmed, []byte("\n"))
+	if bytes.ContainsAny(firstLine, ",;\t") && !bytes.HasPrefix(firstLine, []byte("#")) {
+		return URLFormatCSV
+	}
+	return URLFormatText
+}
+
+// isRemote reports whether path is an http(s) URL rather than a local file.
+func isRemote(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// isGzip reports whether data starts with the gzip magic number.
+func isGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// parseJSONURLs reads a JSON array of URL strings and target objects (see models.Target).
+// Other entries are rejected.
+func parseJSONURLs(data []byte, c *urlCollector) error {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		var target models.Target
+		if err := json.Unmarshal(entry, &target); err != nil {
+			c.reject(i+1, string(entry), "not a URL string or target object")
+			continue
+		}
+		value := target.URL
+		if target.HasOptions() {
+			value = string(entry)
+		}
+		c.addTarget(i+1, value, target)
+	}
+	return nil
+}
+
+// parseTextURLs reads one URL per line. Empty lines and comments ("#" at the start of
+// a line or after whitespace) are ignored.
+func parseTextURLs(data []byte, c *urlCollector) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if i := strings.Index(text, " #"); i >= 0 {
+			text = text[:i]
+		}
+		if i := strings.Index(text, "\t#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		c.add(line, text)
+	}
+}
+
+// csvURLColumns are header names (lowercase) that identify the URL column.
+var csvURLColumns = []string{"url", "urls", "link", "href", "loc", "address"}
+
+// parseCSVURLs reads URLs from a CSV table. The delimiter (comma, semicolon or tab)
+// is sniffed from the first line. If a header names a URL column it is used;
+// otherwise the first column holds the URLs.
+func parseCSVURLs(data []byte, c *urlCollector) error {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = sniffCSVDelimiter(firstLine)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.Comment = '#'
+
+	column := 0
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if row == 1 {
+			if header, ok := csvHeaderColumn(record); ok {
+				column = header
+				continue
+			}
+		}
+		if column >= len(record) {
+			c.reject(line, strings.Join(record, string(reader.Comma)), "missing URL column")
+			continue
+		}
+		if strings.TrimSpace(record[column]) == "" {
+			continue
+		}
+		c.add(line, record[column])
+	}
+}
+
+// sniffCSVDelimiter picks the most frequent candidate delimiter of the first line.
+func sniffCSVDelimiter(firstLine []byte) rune {
+	best, bestCount := ',', 0
+	for _, delimiter := range []rune{',', ';', '\t'} {
+		if count := bytes.Count(firstLine, []byte(string(delimiter))); count > bestCount {
+			best, bestCount = delimiter, count
+		}
+	}
+	return best
+}
+
+// csvHeaderColumn returns the index of the URL column if record is a header row.
+func csvHeaderColumn(record []string) (int, bool) {
+	for i, field := range record {
+		name := strings.ToLower(strings.TrimSpace(field))
+		for _, candidate := range csvURLColumns {
+			if name == candidate {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// sitemapDocument covers both <urlset> sitemaps and <sitemapindex> files.
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+// sitemapLoc is a <url> or <sitemap> entry.
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// parseSitemap reads a sitemap or sitemap index. The sitemaps referenced by an index
+// are loaded as well: relative locations from the filesystem, http(s) locations via
+// Fetch. Sitemaps that cannot be loaded are reported as rejected entries.
+func (l URLLoader) parseSitemap(data []byte, source string, c *urlCollector, depth int) error {
+	if isGzip(data) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return err
+		}
+	}
+
+	var doc sitemapDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	switch doc.XMLName.Local {
+	case "urlset":
+		for i, entry := range doc.URLs {
+			c.add(i+1, entry.Loc)
+		}
+	case "sitemapindex":
+		for i, entry := range doc.Sitemaps {
+			loc := strings.TrimSpace(entry.Loc)
+			if depth >= maxSitemapDepth {
+				c.reject(i+1, loc, "sitemap index nested too deeply")
+				continue
+			}
+			child, err := l.read(l.resolveSitemap(source, loc))
+			if err == nil {
+				err = l.parseSitemap(child, loc, c, depth+1)
+			}
+			if err != nil {
+				c.reject(i+1, loc, fmt.Sprintf("sitemap could not be loaded: %v", err))
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected root element <%s>, expected <urlset> or <sitemapindex>", doc.XMLName.Local)
+	}
+	return nil
+}
+
+// resolveSitemap resolves a sitemap location from an index. Remote locations are used
+// as is unless the index itself is local: then a file with the same name next to the
+// index is preferred when it exists, so mirrored sitemap sets work offline.
+func (l URLLoader) resolveSitemap(index, loc string) string {
+	if isRemote(loc) {
+		if !isRemote(index) && index != StdinPath {
+			local := filepath.Join(filepath.Dir(index), filepath.Base(loc))
+			if _, err := l.FS.Stat(local); err == nil {
+				return local
+			}
+		}
+		return loc
+	}
+	if isRemote(index) {
+		if base, err := url.Parse(index); err == nil {
+			if ref, err := base.Parse(loc); err == nil {
+				return ref.String()
+			}
+		}
+		return loc
+	}
+	if filepath.IsAbs(loc) || index == StdinPath {
+		return loc
+	}
+	return filepath.Join(filepath.Dir(index), loc)
+}
@@ -0,0 +1,251 @@
+package util_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"go-scraper/models"
+	"go-scraper/util"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestURLLoader_Formats(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		format   util.URLFormat
+		expected []string
+		rejected int
+	}{
+		{
+			name:     "json array",
+			path:     "urls.json",
+			content:  `["https://a.com", " https://b.com ", 42, "ftp://c.com"]`,
+			format:   util.URLFormatJSON,
+			expected: []string{"https://a.com", "https://b.com"},
+			rejected: 2,
+		},
+		{
+			name:     "plain text with comments",
+			path:     "urls.txt",
+			content:  "# seeds\nhttps://a.com\n\n  https://b.com  # second\nnot a url\nhttps://a.com\n",
+			format:   util.URLFormatText,
+			expected: []string{"https://a.com", "https://b.com"},
+			rejected: 1,
+		},
+		{
+			name:     "csv with url column",
+			path:     "urls.csv",
+			content:  "name,url\nA,https://a.com\nB,\"https://b.com\"\nC,mailto:c@c.com\n",
+			format:   util.URLFormatCSV,
+			expected: []string{"https://a.com", "https://b.com"},
+			rejected: 1,
+		},
+		{
+			name:     "semicolon csv without header",
+			path:     "urls.csv",
+			content:  "https://a.com;first\nhttps://b.com;second\n",
+			format:   util.URLFormatCSV,
+			expected: []string{"https://a.com", "https://b.com"},
+		},
+		{
+			name: "sitemap",
+			path: "sitemap.xml",
+			content: `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://a.com/</loc></url>
+  <url><loc> https://a.com/about </loc></url>
+</urlset>`,
+			format:   util.URLFormatSitemap,
+			expected: []string{"https://a.com/", "https://a.com/about"},
+		},
+		{
+			name:     "sniffed text",
+			path:     "seeds",
+			content:  "https://a.com\nhttps://b.com",
+			format:   util.URLFormatText,
+			expected: []string{"https://a.com", "https://b.com"},
+		},
+		{
+			name:     "sniffed json",
+			path:     "seeds",
+			content:  "\n [\"https://a.com\"]",
+			format:   util.URLFormatJSON,
+			expected: []string{"https://a.com"},
+		},
+		{
+			name:     "sniffed csv",
+			path:     "seeds",
+			content:  "id\turl\n1\thttps://a.com\n",
+			format:   util.URLFormatCSV,
+			expected: []string{"https://a.com"},
+		},
+		{
+			name:     "sniffed sitemap",
+			path:     "seeds",
+			content:  "<urlset><url><loc>https://a.com</loc></url></urlset>",
+			format:   util.URLFormatSitemap,
+			expected: []string{"https://a.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newMockFS()
+			fs.files[tt.path] = []byte(tt.content)
+
+			list, err := util.URLLoader{FS: fs}.Load(tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if list.Format != tt.format {
+				t.Errorf("expected format %s, got %s", tt.format, list.Format)
+			}
+			if !reflect.DeepEqual(list.URLs(), tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, list.URLs())
+			}
+			if len(list.Rejected) != tt.rejected {
+				t.Errorf("expected %d rejected entries, got %v", tt.rejected, list.Rejected)
+			}
+		})
+	}
+}
+
+func TestURLLoader_RejectedAndDuplicates(t *testing.T) {
+	fs := newMockFS()
+	fs.files["urls.txt"] = []byte("https://a.com\nhttps://a.com\n/relative\nhttps://b.com\n https://b.com\n")
+
+	list, err := util.URLLoader{FS: fs}.Load("urls.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Duplicates != 2 {
+		t.Errorf("expected 2 duplicates, got %d", list.Duplicates)
+	}
+	expected := []util.RejectedURL{{Line: 3, Value: "/relative", Reason: "not an absolute http(s) URL"}}
+	if !reflect.DeepEqual(list.Rejected, expected) {
+		t.Errorf("expected %v, got %v", expected, list.Rejected)
+	}
+}
+
+func TestURLLoader_TargetObjects(t *testing.T) {
+	fs := newMockFS()
+	fs.files["urls.json"] = []byte(`[
+  "https://a.com",
+  {"url": " https://b.com ", "headers": {"Accept-Language": "de"}, "tags": ["news"], "timeoutSeconds": 5},
+  {"tags": ["orphan"]}
+]`)
+
+	list, err := util.URLLoader{FS: fs}.Load("urls.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []models.Target{
+		{URL: "https://a.com"},
+		{URL: "https://b.com", Headers: map[string]string{"Accept-Language": "de"}, Tags: []string{"news"}, TimeoutSeconds: 5},
+	}
+	if !reflect.DeepEqual(list.Targets, expected) {
+		t.Errorf("expected %+v, got %+v", expected, list.Targets)
+	}
+	if len(list.Rejected) != 1 || list.Rejected[0].Line != 3 {
+		t.Errorf("expected the object without url to be rejected, got %v", list.Rejected)
+	}
+}
+
+func TestURLLoader_Stdin(t *testing.T) {
+	loader := util.URLLoader{FS: newMockFS(), Stdin: strings.NewReader("https://a.com\nhttps://b.com\n")}
+
+	list, err := loader.Load(util.StdinPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Format != util.URLFormatText || len(list.URLs()) != 2 {
+		t.Errorf("unexpected result: %+v", list)
+	}
+}
+
+func TestURLLoader_SitemapIndex(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte("<urlset><url><loc>https://a.com/blog</loc></url></urlset>"))
+	_ = zw.Close()
+
+	fs := newMockFS()
+	fs.files["maps/sitemap.xml"] = []byte(`<sitemapindex>
+  <sitemap><loc>pages.xml</loc></sitemap>
+  <sitemap><loc>https://a.com/blog.xml.gz</loc></sitemap>
+  <sitemap><loc>https://a.com/missing.xml</loc></sitemap>
+</sitemapindex>`)
+	fs.files["maps/pages.xml"] = []byte("<urlset><url><loc>https://a.com/</loc></url></urlset>")
+
+	fetched := map[string][]byte{"https://a.com/blog.xml.gz": gz.Bytes()}
+	loader := util.URLLoader{FS: fs, Fetch: func(rawURL string) ([]byte, error) {
+		if data, ok := fetched[rawURL]; ok {
+			return data, nil
+		}
+		return nil, errors.New("404 Not Found")
+	}}
+
+	list, err := loader.Load("maps/sitemap.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"https://a.com/", "https://a.com/blog"}
+	if !reflect.DeepEqual(list.URLs(), expected) {
+		t.Errorf("expected %v, got %v", expected, list.URLs())
+	}
+	if len(list.Rejected) != 1 || list.Rejected[0].Value != "https://a.com/missing.xml" {
+		t.Errorf("expected the missing sitemap to be rejected, got %v", list.Rejected)
+	}
+}
+
+func TestURLLoader_MissingFile(t *testing.T) {
+	fs := newMockFS()
+
+	// A missing JSON URL file is created empty, like GetTargetsFromFile does
+	list, err := util.URLLoader{FS: fs}.Load("urls.json")
+	if err != nil || len(list.URLs()) != 0 {
+		t.Fatalf("expected an empty list, got %v, %v", list, err)
+	}
+	if _, ok := fs.files["urls.json"]; !ok {
+		t.Error("expected urls.json to be created")
+	}
+
+	if _, err := (util.URLLoader{FS: fs}).Load("urls.txt"); err == nil {
+		t.Error("expected an error for a missing text file")
+	}
+}
+
+func TestURLLoader_InvalidContent(t *testing.T) {
+	fs := newMockFS()
+	fs.files["urls.json"] = []byte(`{"url": "https://a.com"}`)
+	fs.files["sitemap.xml"] = []byte("<rss></rss>")
+
+	for _, path := range []string{"urls.json", "sitemap.xml"} {
+		if _, err := (util.URLLoader{FS: fs}).Load(path); err == nil {
+			t.Errorf("%s: expected an error", path)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		raw   string
+		valid bool
+	}{
+		{"https://example.com/path?q=1", true},
+		{"http://localhost:8080", true},
+		{"example.com", false},
+		{"ftp://example.com", false},
+		{"https://", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if err := util.ValidateURL(tt.raw); (err == nil) != tt.valid {
+			t.Errorf("ValidateURL(%q): expected valid=%v, got %v", tt.raw, tt.valid, err)
+		}
+	}
+}